@@ -94,8 +94,7 @@ func run(app pancake.App) error {
 		ebo := pancake.NewIndexBufferUint8(indices)
 		vslice := pancake.NewIndexedVertexArraySlice(ebo, buffer)
 
-		gl.Enable(gl.BLEND)
-		gl.BlendFunc(gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
+		pancake.BlendPremultiplied.Begin()
 
 		app.Scissor(app.Bounds()).Begin()
 
@@ -0,0 +1,163 @@
+// Package actions implements rebindable controls on top of pancake's input types.
+//
+// Named actions are bound to one or more keys, mouse buttons or chords.
+// Call Map.Update once per pancake.FrameEvent with the pancake.InputState
+// and then query the actions:
+//
+//	m := actions.NewMap()
+//	m.Bind("save", actions.Key(pancake.KeyS, pancake.ModControl))
+//	m.Bind("left", actions.Key(pancake.KeyA, 0), actions.Key(pancake.KeyLeft, 0))
+//	m.Bind("right", actions.Key(pancake.KeyD, 0), actions.Key(pancake.KeyRight, 0))
+//	m.BindAxis("horizontal", "left", "right")
+//	...
+//	case pancake.FrameEvent:
+//		m.Update(app.InputState())
+//		if m.Pressed("save") { ... }
+//		x += m.Axis("horizontal") * speed
+package actions
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+type actionState struct {
+	held, pressed, released bool
+}
+
+type axis struct {
+	Negative string `json:"negative"`
+	Positive string `json:"positive"`
+}
+
+// Map maps named actions to bindings and tracks their state per frame.
+type Map struct {
+	bindings map[string][]Binding
+	axes     map[string]axis
+	states   map[string]actionState
+}
+
+// NewMap creates an empty action Map.
+func NewMap() *Map {
+	return &Map{
+		bindings: map[string][]Binding{},
+		axes:     map[string]axis{},
+		states:   map[string]actionState{},
+	}
+}
+
+// Bind adds bindings to an action.
+func (m *Map) Bind(action string, bindings ...Binding) {
+	m.bindings[action] = append(m.bindings[action], bindings...)
+}
+
+// Rebind replaces all bindings of an action.
+func (m *Map) Rebind(action string, bindings ...Binding) {
+	m.bindings[action] = append([]Binding(nil), bindings...)
+}
+
+// Unbind removes all bindings of an action.
+func (m *Map) Unbind(action string) {
+	delete(m.bindings, action)
+	delete(m.states, action)
+}
+
+// Bindings returns the bindings of an action.
+func (m *Map) Bindings(action string) []Binding {
+	return append([]Binding(nil), m.bindings[action]...)
+}
+
+// Actions returns the names of all bound actions in sorted order.
+func (m *Map) Actions() []string {
+	names := make([]string, 0, len(m.bindings))
+	for name := range m.bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BindAxis creates an axis that is composed of two actions.
+// The axis reports -1 while the negative action is held,
+// +1 while the positive action is held and 0 if both or neither are held.
+func (m *Map) BindAxis(name, negative, positive string) {
+	m.axes[name] = axis{negative, positive}
+}
+
+// Update evaluates all actions against the input state.
+// It must be called once per frame.
+func (m *Map) Update(s State) {
+	mods := modifiersDown(s)
+	for action, bindings := range m.bindings {
+		var next actionState
+		for _, b := range bindings {
+			held, pressed := b.eval(s, mods)
+			next.held = next.held || held
+			next.pressed = next.pressed || pressed
+		}
+		prev := m.states[action]
+		next.released = (prev.held || next.pressed) && !next.held
+		m.states[action] = next
+	}
+}
+
+// Held reports whether any binding of the action is being held down.
+func (m *Map) Held(action string) bool {
+	return m.states[action].held
+}
+
+// Pressed reports whether the action was pressed this frame.
+func (m *Map) Pressed(action string) bool {
+	return m.states[action].pressed
+}
+
+// Released reports whether the action was released this frame.
+func (m *Map) Released(action string) bool {
+	return m.states[action].released
+}
+
+// Axis reports the value of an axis.
+func (m *Map) Axis(name string) float64 {
+	var value float64
+	if a, ok := m.axes[name]; ok {
+		if m.Held(a.Negative) {
+			value--
+		}
+		if m.Held(a.Positive) {
+			value++
+		}
+	}
+	return value
+}
+
+type mapJSON struct {
+	Actions map[string][]Binding `json:"actions"`
+	Axes    map[string]axis      `json:"axes,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m *Map) MarshalJSON() ([]byte, error) {
+	return json.Marshal(mapJSON{
+		Actions: m.bindings,
+		Axes:    m.axes,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// It replaces all bindings and axes and resets the action states.
+func (m *Map) UnmarshalJSON(data []byte) error {
+	var v mapJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Actions == nil {
+		v.Actions = map[string][]Binding{}
+	}
+	if v.Axes == nil {
+		v.Axes = map[string]axis{}
+	}
+	m.bindings = v.Actions
+	m.axes = v.Axes
+	m.states = map[string]actionState{}
+	return nil
+}
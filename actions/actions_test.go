@@ -0,0 +1,118 @@
+package actions
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/askeladdk/pancake"
+)
+
+type fakeState struct {
+	down, pressed map[pancake.Key]bool
+	mouse         map[pancake.MouseButton]bool
+}
+
+func (s fakeState) KeyDown(k pancake.Key) bool              { return s.down[k] }
+func (s fakeState) KeyPressed(k pancake.Key) bool           { return s.pressed[k] }
+func (s fakeState) MouseDown(b pancake.MouseButton) bool    { return s.mouse[b] }
+func (s fakeState) MousePressed(b pancake.MouseButton) bool { return false }
+
+func keys(ks ...pancake.Key) map[pancake.Key]bool {
+	m := map[pancake.Key]bool{}
+	for _, k := range ks {
+		m[k] = true
+	}
+	return m
+}
+
+func TestParseBinding(t *testing.T) {
+	tests := []struct {
+		text    string
+		binding Binding
+	}{
+		{"Space", Key(pancake.KeySpace, 0)},
+		{"Ctrl+S", Key(pancake.KeyS, pancake.ModControl)},
+		{"Ctrl+Shift+Escape", Key(pancake.KeyEscape, pancake.ModControl|pancake.ModShift)},
+		{"Alt+Mouse1", Mouse(pancake.MouseButton1, pancake.ModAlt)},
+	}
+
+	for _, x := range tests {
+		if b, err := ParseBinding(x.text); err != nil {
+			t.Fatal(err)
+		} else if b != x.binding {
+			t.Fatal(x.text)
+		} else if b.String() != x.text {
+			t.Fatal(b.String())
+		}
+	}
+
+	for _, text := range []string{"", "Ctrl+", "Hyper+A", "Mouse9", "Nope"} {
+		if _, err := ParseBinding(text); err == nil {
+			t.Fatal(text)
+		}
+	}
+}
+
+func TestMap(t *testing.T) {
+	m := NewMap()
+	m.Bind("save", Key(pancake.KeyS, pancake.ModControl))
+	m.Bind("down", Key(pancake.KeyS, 0))
+	m.Bind("up", Key(pancake.KeyW, 0))
+	m.Bind("fire", Mouse(pancake.MouseButton0, 0))
+	m.BindAxis("vertical", "up", "down")
+
+	m.Update(fakeState{down: keys(pancake.KeyS), pressed: keys(pancake.KeyS)})
+	if m.Pressed("save") || !m.Pressed("down") || !m.Held("down") {
+		t.Fatal("s")
+	} else if m.Axis("vertical") != 1 {
+		t.Fatal(m.Axis("vertical"))
+	}
+
+	m.Update(fakeState{down: keys(pancake.KeyS, pancake.KeyLeftControl), pressed: keys(pancake.KeyLeftControl)})
+	if !m.Held("save") || m.Pressed("save") || m.Pressed("down") || !m.Held("down") {
+		t.Fatal("ctrl+s held")
+	}
+
+	m.Update(fakeState{mouse: map[pancake.MouseButton]bool{pancake.MouseButton0: true}})
+	if !m.Released("save") || !m.Released("down") || !m.Held("fire") {
+		t.Fatal("released")
+	} else if m.Axis("vertical") != 0 {
+		t.Fatal(m.Axis("vertical"))
+	}
+
+	m.Update(fakeState{pressed: keys(pancake.KeyW)})
+	if !m.Pressed("up") || !m.Released("up") || m.Held("up") {
+		t.Fatal("tap")
+	}
+}
+
+func TestMapJSON(t *testing.T) {
+	m := NewMap()
+	m.Bind("save", Key(pancake.KeyS, pancake.ModControl))
+	m.Bind("left", Key(pancake.KeyA, 0), Key(pancake.KeyLeft, 0))
+	m.Bind("right", Key(pancake.KeyD, 0))
+	m.BindAxis("horizontal", "left", "right")
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := NewMap()
+	if err := json.Unmarshal(data, n); err != nil {
+		t.Fatal(err)
+	} else if bs := n.Bindings("left"); len(bs) != 2 || bs[1] != Key(pancake.KeyLeft, 0) {
+		t.Fatal(bs)
+	} else if bs := n.Bindings("save"); len(bs) != 1 || bs[0] != Key(pancake.KeyS, pancake.ModControl) {
+		t.Fatal(bs)
+	}
+
+	n.Update(fakeState{down: keys(pancake.KeyLeft)})
+	if n.Axis("horizontal") != -1 {
+		t.Fatal(n.Axis("horizontal"))
+	}
+
+	if err := json.Unmarshal([]byte(`{"actions":{"x":["Hyper+X"]}}`), n); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,187 @@
+package actions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/askeladdk/pancake"
+)
+
+// modifierMask are the modifiers that can be part of a chord.
+const modifierMask = pancake.ModShift | pancake.ModControl | pancake.ModAlt | pancake.ModSuper
+
+var modifierNames = []struct {
+	mod  pancake.Modifiers
+	name string
+}{
+	{pancake.ModControl, "Ctrl"},
+	{pancake.ModShift, "Shift"},
+	{pancake.ModAlt, "Alt"},
+	{pancake.ModSuper, "Super"},
+}
+
+const mousePrefix = "Mouse"
+
+// Binding binds a key or mouse button, optionally combined with modifiers, to an action.
+// A Binding without modifiers triggers regardless of the modifiers being held.
+// A Binding with modifiers forms a chord that only triggers while all of its modifiers are held.
+//
+// Bindings are serialized as text such as "Space", "Ctrl+S" or "Shift+Mouse1".
+type Binding struct {
+	key       pancake.Key
+	button    pancake.MouseButton
+	mouse     bool
+	modifiers pancake.Modifiers
+}
+
+// Key creates a key Binding.
+func Key(key pancake.Key, modifiers pancake.Modifiers) Binding {
+	return Binding{
+		key:       key,
+		modifiers: modifiers & modifierMask,
+	}
+}
+
+// Mouse creates a mouse button Binding.
+func Mouse(button pancake.MouseButton, modifiers pancake.Modifiers) Binding {
+	return Binding{
+		button:    button,
+		mouse:     true,
+		modifiers: modifiers & modifierMask,
+	}
+}
+
+// ParseBinding parses the text representation of a Binding.
+func ParseBinding(text string) (Binding, error) {
+	var b Binding
+
+	parts := strings.Split(text, "+")
+	for _, part := range parts[:len(parts)-1] {
+		mod, ok := parseModifier(part)
+		if !ok {
+			return Binding{}, fmt.Errorf("actions: invalid modifier %q in binding %q", part, text)
+		}
+		b.modifiers |= mod
+	}
+
+	name := parts[len(parts)-1]
+	if key, ok := parseKey(name); ok {
+		b.key = key
+	} else if n, err := strconv.Atoi(strings.TrimPrefix(name, mousePrefix)); strings.HasPrefix(name, mousePrefix) && err == nil &&
+		n >= int(pancake.MouseButton0) && n <= int(pancake.MouseButton7) {
+		b.button = pancake.MouseButton(n)
+		b.mouse = true
+	} else {
+		return Binding{}, fmt.Errorf("actions: invalid key or button %q in binding %q", name, text)
+	}
+
+	return b, nil
+}
+
+// Modifiers returns the modifiers of the chord.
+func (b Binding) Modifiers() pancake.Modifiers {
+	return b.modifiers
+}
+
+// Key returns the key and whether the Binding is a key binding.
+func (b Binding) Key() (pancake.Key, bool) {
+	return b.key, !b.mouse
+}
+
+// MouseButton returns the mouse button and whether the Binding is a mouse binding.
+func (b Binding) MouseButton() (pancake.MouseButton, bool) {
+	return b.button, b.mouse
+}
+
+// String returns the text representation of the Binding.
+func (b Binding) String() string {
+	var sb strings.Builder
+	for _, m := range modifierNames {
+		if b.modifiers&m.mod != 0 {
+			sb.WriteString(m.name)
+			sb.WriteByte('+')
+		}
+	}
+
+	if b.mouse {
+		sb.WriteString(mousePrefix)
+		sb.WriteString(strconv.Itoa(int(b.button)))
+	} else if name, ok := keyNames[b.key]; ok {
+		sb.WriteString(name)
+	} else {
+		sb.WriteString("Unknown")
+	}
+
+	return sb.String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (b Binding) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (b *Binding) UnmarshalText(text []byte) error {
+	binding, err := ParseBinding(string(text))
+	if err != nil {
+		return err
+	}
+	*b = binding
+	return nil
+}
+
+func parseModifier(name string) (pancake.Modifiers, bool) {
+	for _, m := range modifierNames {
+		if strings.EqualFold(name, m.name) {
+			return m.mod, true
+		}
+	}
+	return 0, false
+}
+
+func parseKey(name string) (pancake.Key, bool) {
+	for key, keyName := range keyNames {
+		if strings.EqualFold(name, keyName) {
+			return key, true
+		}
+	}
+	return pancake.KeyUnknown, false
+}
+
+// State is the input state that actions are evaluated against.
+// It is implemented by pancake.InputState.
+type State interface {
+	KeyDown(pancake.Key) bool
+	KeyPressed(pancake.Key) bool
+	MouseDown(pancake.MouseButton) bool
+	MousePressed(pancake.MouseButton) bool
+}
+
+var _ State = pancake.InputState{}
+
+func modifiersDown(s State) (mods pancake.Modifiers) {
+	if s.KeyDown(pancake.KeyLeftShift) || s.KeyDown(pancake.KeyRightShift) {
+		mods |= pancake.ModShift
+	}
+	if s.KeyDown(pancake.KeyLeftControl) || s.KeyDown(pancake.KeyRightControl) {
+		mods |= pancake.ModControl
+	}
+	if s.KeyDown(pancake.KeyLeftAlt) || s.KeyDown(pancake.KeyRightAlt) {
+		mods |= pancake.ModAlt
+	}
+	if s.KeyDown(pancake.KeyLeftSuper) || s.KeyDown(pancake.KeyRightSuper) {
+		mods |= pancake.ModSuper
+	}
+	return mods
+}
+
+// eval reports whether the binding is held and whether it was pressed this frame.
+func (b Binding) eval(s State, mods pancake.Modifiers) (held, pressed bool) {
+	if mods&b.modifiers != b.modifiers {
+		return false, false
+	} else if b.mouse {
+		return s.MouseDown(b.button), s.MousePressed(b.button)
+	}
+	return s.KeyDown(b.key), s.KeyPressed(b.key)
+}
@@ -0,0 +1,113 @@
+package actions
+
+import "github.com/askeladdk/pancake"
+
+var keyNames = map[pancake.Key]string{
+	pancake.Key0:            "0",
+	pancake.Key1:            "1",
+	pancake.Key2:            "2",
+	pancake.Key3:            "3",
+	pancake.Key4:            "4",
+	pancake.Key5:            "5",
+	pancake.Key6:            "6",
+	pancake.Key7:            "7",
+	pancake.Key8:            "8",
+	pancake.Key9:            "9",
+	pancake.KeyA:            "A",
+	pancake.KeyApostrophe:   "Apostrophe",
+	pancake.KeyB:            "B",
+	pancake.KeyBackslash:    "Backslash",
+	pancake.KeyBackspace:    "Backspace",
+	pancake.KeyC:            "C",
+	pancake.KeyCapsLock:     "CapsLock",
+	pancake.KeyComma:        "Comma",
+	pancake.KeyD:            "D",
+	pancake.KeyDelete:       "Delete",
+	pancake.KeyDown:         "Down",
+	pancake.KeyE:            "E",
+	pancake.KeyEnd:          "End",
+	pancake.KeyEnter:        "Enter",
+	pancake.KeyEqual:        "Equal",
+	pancake.KeyEscape:       "Escape",
+	pancake.KeyF:            "F",
+	pancake.KeyF1:           "F1",
+	pancake.KeyF10:          "F10",
+	pancake.KeyF11:          "F11",
+	pancake.KeyF12:          "F12",
+	pancake.KeyF2:           "F2",
+	pancake.KeyF3:           "F3",
+	pancake.KeyF4:           "F4",
+	pancake.KeyF5:           "F5",
+	pancake.KeyF6:           "F6",
+	pancake.KeyF7:           "F7",
+	pancake.KeyF8:           "F8",
+	pancake.KeyF9:           "F9",
+	pancake.KeyG:            "G",
+	pancake.KeyGraveAccent:  "GraveAccent",
+	pancake.KeyH:            "H",
+	pancake.KeyHome:         "Home",
+	pancake.KeyI:            "I",
+	pancake.KeyInsert:       "Insert",
+	pancake.KeyJ:            "J",
+	pancake.KeyK:            "K",
+	pancake.KeyKP0:          "KP0",
+	pancake.KeyKP1:          "KP1",
+	pancake.KeyKP2:          "KP2",
+	pancake.KeyKP3:          "KP3",
+	pancake.KeyKP4:          "KP4",
+	pancake.KeyKP5:          "KP5",
+	pancake.KeyKP6:          "KP6",
+	pancake.KeyKP7:          "KP7",
+	pancake.KeyKP8:          "KP8",
+	pancake.KeyKP9:          "KP9",
+	pancake.KeyKPAdd:        "KPAdd",
+	pancake.KeyKPDecimal:    "KPDecimal",
+	pancake.KeyKPDivide:     "KPDivide",
+	pancake.KeyKPEnter:      "KPEnter",
+	pancake.KeyKPEqual:      "KPEqual",
+	pancake.KeyKPMultiply:   "KPMultiply",
+	pancake.KeyKPSubtract:   "KPSubtract",
+	pancake.KeyL:            "L",
+	pancake.KeyLeft:         "Left",
+	pancake.KeyLeftAlt:      "LeftAlt",
+	pancake.KeyLeftBracket:  "LeftBracket",
+	pancake.KeyLeftControl:  "LeftControl",
+	pancake.KeyLeftShift:    "LeftShift",
+	pancake.KeyLeftSuper:    "LeftSuper",
+	pancake.KeyM:            "M",
+	pancake.KeyMenu:         "Menu",
+	pancake.KeyMinus:        "Minus",
+	pancake.KeyN:            "N",
+	pancake.KeyNumLock:      "NumLock",
+	pancake.KeyO:            "O",
+	pancake.KeyP:            "P",
+	pancake.KeyPageDown:     "PageDown",
+	pancake.KeyPageUp:       "PageUp",
+	pancake.KeyPause:        "Pause",
+	pancake.KeyPeriod:       "Period",
+	pancake.KeyPrintScreen:  "PrintScreen",
+	pancake.KeyQ:            "Q",
+	pancake.KeyR:            "R",
+	pancake.KeyRight:        "Right",
+	pancake.KeyRightAlt:     "RightAlt",
+	pancake.KeyRightBracket: "RightBracket",
+	pancake.KeyRightControl: "RightControl",
+	pancake.KeyRightShift:   "RightShift",
+	pancake.KeyRightSuper:   "RightSuper",
+	pancake.KeyS:            "S",
+	pancake.KeyScrollLock:   "ScrollLock",
+	pancake.KeySemicolon:    "Semicolon",
+	pancake.KeySlash:        "Slash",
+	pancake.KeySpace:        "Space",
+	pancake.KeyT:            "T",
+	pancake.KeyTab:          "Tab",
+	pancake.KeyU:            "U",
+	pancake.KeyUp:           "Up",
+	pancake.KeyV:            "V",
+	pancake.KeyW:            "W",
+	pancake.KeyWorld1:       "World1",
+	pancake.KeyWorld2:       "World2",
+	pancake.KeyX:            "X",
+	pancake.KeyY:            "Y",
+	pancake.KeyZ:            "Z",
+}
@@ -3,8 +3,12 @@ package pancake
 import (
 	"context"
 	"image"
+	"io"
+	"log"
+	"sync"
 	"time"
 
+	gl "github.com/askeladdk/pancake/opengl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"golang.design/x/mainthread"
 )
@@ -23,6 +27,16 @@ type App interface {
 	// FrameRate reports the current framerate.
 	FrameRate() int
 
+	// SetFrameRate changes the target frame rate.
+	SetFrameRate(int)
+
+	// Stats reports the frame timing statistics.
+	Stats() FrameStats
+
+	// FrameTimes appends the frame times of the last FrameTimeWindow frames
+	// from oldest to newest.
+	FrameTimes([]time.Duration) []time.Duration
+
 	// Scissor creates a Scissor that is scaled to the viewport.
 	Scissor(image.Rectangle) Scissor
 
@@ -31,63 +45,238 @@ type App interface {
 
 	// SetTitle sets the window title.
 	SetTitle(string)
+
+	// InputState returns a snapshot of the keyboard and mouse state
+	// as of the most recent FrameEvent.
+	InputState() InputState
+
+	// Screenshot reads back the viewport of the default framebuffer.
+	Screenshot() *image.RGBA
+
+	// Fullscreen reports whether the window is in fullscreen mode.
+	Fullscreen() bool
+
+	// SetFullscreen switches between fullscreen and windowed mode.
+	SetFullscreen(bool)
+
+	// SetUpscaleShader replaces the shader that presents the internal framebuffer.
+	// It has no effect if Options.Upscale is UpscaleNone.
+	// See UpscaleVertexShader for the shader interface.
+	SetUpscaleShader(*ShaderProgram)
+
+	// Do runs fn on the goroutine that runs the App when the current frame ends,
+	// or when that goroutine next binds a resource if it does not end frames,
+	// so that other goroutines can safely create and update GL resources.
+	// The returned channel is closed after fn has run.
+	// Do must not be waited on from the goroutine that runs the App.
+	//
+	//	go func() {
+	//		img := decode(file)
+	//		var tex *pancake.Texture
+	//		<-app.Do(func() { tex = pancake.NewTextureFromImage(img, pancake.FilterLinear) })
+	//		textures <- tex
+	//	}()
+	Do(fn func()) <-chan struct{}
 }
 
 type app struct {
 	*glfwWindow
-	deltaTime float64
-	frameRate int
-	eventch   chan interface{}
+	mu           sync.Mutex
+	deltaTime    float64
+	drawDelta    float64
+	frameRate    int
+	loopMode     LoopMode
+	maxFrameSkip int
+	eventch      chan interface{}
+	input        InputState
+	inputFrame   InputState
+	frame        int
+	frameCount   int
+	frameTime    time.Time
+	recorder     *inputRecorder
+	replay       *inputReplay
+}
+
+func (app *app) FrameRate() int {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	return app.frameRate
 }
 
-func (app *app) FrameRate() int { return app.frameRate }
+func (app *app) SetFrameRate(frameRate int) {
+	if frameRate > 0 {
+		app.mu.Lock()
+		app.deltaTime = 1 / float64(frameRate)
+		app.mu.Unlock()
+	}
+}
+
+func (app *app) Stats() FrameStats {
+	stats := app.frameTimer.stats()
+	stats.UpdateRate = app.FrameRate()
+	return stats
+}
+
+func (app *app) FrameTimes(dst []time.Duration) []time.Duration {
+	return app.frameTimer.frameTimes(dst)
+}
 
 func (app *app) Events() <-chan interface{} { return app.eventch }
 
+func (app *app) Do(fn func()) <-chan struct{} {
+	done := make(chan struct{})
+	queueCall(func() {
+		defer close(done)
+		fn()
+	})
+	return done
+}
+
+func (app *app) InputState() InputState {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	return app.inputFrame
+}
+
+func (app *app) targetDeltaTime() float64 {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	return app.deltaTime
+}
+
+func (app *app) send(ctx context.Context, event interface{}) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case app.eventch <- event:
+		return true
+	}
+}
+
+// update emits the input events of one frame followed by a FrameEvent.
+func (app *app) update(ctx context.Context, deltaTime float64) bool {
+	events := app.pollEvents()
+	if app.replay != nil {
+		events = app.replay.replace(app.frame, events)
+	}
+
+	for _, event := range events {
+		if app.recorder != nil {
+			app.recorder.record(app.frame, event)
+		}
+		app.input.update(event)
+		if !app.send(ctx, event) {
+			return false
+		}
+	}
+
+	// publish the input state in step with the frame event
+	app.mu.Lock()
+	app.inputFrame = app.input
+	app.mu.Unlock()
+	app.input.endFrame()
+
+	if app.replay != nil {
+		deltaTime = app.replay.deltaTime(app.frame, deltaTime)
+	}
+	if app.recorder != nil {
+		app.recorder.recordFrame(app.frame, deltaTime)
+	}
+
+	if !app.send(ctx, FrameEvent{deltaTime}) {
+		return false
+	}
+	app.frame++
+
+	// frame counter
+	app.frameCount++
+	if t := time.Now(); t.Sub(app.frameTime).Seconds() >= 1 {
+		app.mu.Lock()
+		app.frameRate = app.frameCount
+		app.mu.Unlock()
+		app.frameCount, app.frameTime = 0, t
+	}
+
+	return true
+}
+
 func (app *app) loop(ctx context.Context) {
-	// loop regulator variables
-	deltaTime := app.deltaTime
 	accumulator := float64(0)
 	t0 := time.Now()
-
-	// frame counter variables
-	frameRate := 0
-	ft0 := time.Now()
+	app.frameTime = t0
 
 	for {
+		requestFlush()
+
+		deltaTime := app.targetDeltaTime()
 		t1 := time.Now()
-		accumulator += t1.Sub(t0).Seconds()
+		elapsed := t1.Sub(t0).Seconds()
 		t0 = t1
 
-		for accumulator >= deltaTime {
-			accumulator -= deltaTime
+		// cap the elapsed time to avoid the spiral of death
+		if maxElapsed := float64(app.maxFrameSkip) * deltaTime; elapsed > maxElapsed {
+			elapsed = maxElapsed
+		}
+
+		alpha := float64(0)
 
-			if !app.InputEvents(ctx, app.eventch) {
+		switch app.loopMode {
+		case LoopVariable:
+			if !app.update(ctx, elapsed) {
 				return
 			}
-
-			select {
-			case <-ctx.Done():
+		case LoopVSync:
+			if !app.update(ctx, app.refreshDeltaTime) {
 				return
-			case app.eventch <- FrameEvent{deltaTime}:
 			}
-
-			// frame counter
-			frameRate++
-			ft1 := time.Now()
-			if ft1.Sub(ft0).Seconds() >= 1 {
-				app.frameRate, frameRate, ft0 = frameRate, 0, ft1
+		default:
+			accumulator += elapsed
+			for accumulator >= deltaTime {
+				accumulator -= deltaTime
+				if !app.update(ctx, deltaTime) {
+					return
+				}
 			}
+			alpha = accumulator / deltaTime
 		}
 
-		select {
-		case <-ctx.Done():
+		if !app.send(ctx, DrawEvent{alpha}) {
 			return
-		case app.eventch <- DrawEvent{accumulator / deltaTime}:
+		}
+
+		// sleep until the next draw is due instead of spinning,
+		// unless swapping buffers already waits for the vertical refresh
+		interval := app.drawDelta
+		if app.loopMode == LoopVariable {
+			interval = deltaTime
+		}
+		if idle := app.pacer.idle(time.Now(), time.Duration(interval*float64(time.Second))); idle > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(idle):
+			}
 		}
 	}
 }
 
+// LoopMode determines how the event loop paces FrameEvents and DrawEvents.
+type LoopMode int
+
+const (
+	// LoopFixed emits FrameEvents at a fixed timestep of 1/FrameRate
+	// and interpolates DrawEvents in between (default).
+	LoopFixed LoopMode = iota
+
+	// LoopVariable emits one FrameEvent with the measured elapsed time
+	// per DrawEvent at a target rate of FrameRate.
+	LoopVariable
+
+	// LoopVSync enables vertical synchronisation and emits one FrameEvent
+	// per DrawEvent with a fixed timestep of the monitor refresh period.
+	LoopVSync
+)
+
 // Options specifies window options.
 type Options struct {
 	// WindowSize is the size of the window.
@@ -95,13 +284,93 @@ type Options struct {
 	// Resolution is the logical resolution in pixels.
 	// Can be smaller than WindowSize.
 	Resolution image.Point
+	// ScalingMode determines how Resolution is scaled to the window.
+	// Defaults to ScalingInteger.
+	ScalingMode ScalingMode
+	// Upscale renders every frame into an internal framebuffer at Resolution
+	// and presents it to the window using the selected filter.
+	// Defaults to UpscaleNone, which draws directly into the window.
+	Upscale UpscaleFilter
+	// Samples is the number of samples per pixel for multisample anti-aliasing.
+	// It applies to the internal framebuffer if Upscale is set and
+	// to the default framebuffer otherwise. Defaults to no multisampling.
+	Samples int
 	// Title is the window title.
 	Title string
 	// FrameRate is the target frame rate.
 	FrameRate int
+	// LoopMode selects how frames are paced. Defaults to LoopFixed.
+	LoopMode LoopMode
+	// MaxFrameRate is the maximum number of DrawEvents per second if
+	// VSync is disabled or ignored by the driver. Defaults to the refresh rate
+	// of the monitor. In LoopVariable mode DrawEvents follow FrameRate instead.
+	MaxFrameRate int
+	// MaxFrameSkip is the maximum number of FrameEvents that are emitted
+	// to catch up before a DrawEvent. Defaults to 5.
+	MaxFrameSkip int
+	// VSync synchronises buffer swaps with the vertical refresh of the monitor.
+	// It is always enabled in LoopVSync mode.
+	VSync bool
+	// Headless creates an invisible window for offscreen rendering.
+	// Useful for running tests and CI jobs. Note that GLFW still needs
+	// a display connection, for example Xvfb on Linux.
+	Headless bool
+	// Resizable allows the user to resize and maximize the window.
+	Resizable bool
+	// Fullscreen starts the window in fullscreen mode.
+	Fullscreen bool
+	// Monitor is the index of the monitor used in fullscreen mode.
+	// Defaults to the primary monitor.
+	Monitor int
+	// GamepadDeadzone is the distance from the rest position within which
+	// gamepad axes are reported as resting, which filters out the noise of idle sticks.
+	// Defaults to 0.1. Negative values disable the deadzone.
+	GamepadDeadzone float64
+	// GamepadMappings are additional gamepad mappings in the
+	// SDL_GameControllerDB format, one mapping per line.
+	GamepadMappings string
+	// RecordInput records all input events tagged with their frame index.
+	RecordInput io.Writer
+	// ReplayInput replays a recording made with RecordInput in place of live input.
+	// FrameEvents have the recorded DeltaTime so that a replay is deterministic in every LoopMode.
+	// ResizeEvents are not recorded because they follow the live window.
+	// A ReplayEndEvent is emitted when the recording has been fully replayed.
+	ReplayInput io.Reader
+	// ContextAPI selects the API used to create the OpenGL context.
+	// Combine ContextAPIOSMesa with Headless to render in software.
+	ContextAPI ContextAPI
+	// Debug creates a debug context, installs a GL debug message callback
+	// and checks for GL errors after every call, which panics with a *GLError.
+	// It also panics if GL is called from a goroutine other than the one that runs the App,
+	// which other goroutines must go through App.Do for.
+	Debug bool
+	// DebugLogger receives the GL debug messages if Debug is set,
+	// as well as a report of the GL objects that are still alive at shutdown.
+	// It is called on the main thread and must not call into GL.
+	// Defaults to log.Println.
+	DebugLogger func(DebugMessage)
 }
 
+// ContextAPI identifies the API that creates the OpenGL context.
+type ContextAPI int
+
+const (
+	// ContextAPINative uses the native context API of the platform (default).
+	ContextAPINative ContextAPI = iota
+	// ContextAPIEGL uses EGL to create the context.
+	ContextAPIEGL
+	// ContextAPIOSMesa uses OSMesa to create an offscreen software context.
+	ContextAPIOSMesa
+)
+
 // Main initializes the window and starts the event loop.
+//
+// The event loop runs on the main thread, which also owns the GL context.
+// The run function is called on a separate goroutine and every GL call
+// it makes is marshalled to the main thread. Pancake caches the bind state
+// of GL objects, so all GL calls and resources must be used from the
+// goroutine that runs the App. Other goroutines use App.Do to run GL code
+// on that goroutine. Set Options.Debug to enforce this.
 func Main(opt Options, run func(App) error) error {
 	if opt.Resolution == (image.Point{}) {
 		opt.Resolution = opt.WindowSize
@@ -111,6 +380,30 @@ func Main(opt Options, run func(App) error) error {
 		opt.FrameRate = 60
 	}
 
+	if opt.MaxFrameSkip <= 0 {
+		opt.MaxFrameSkip = 5
+	}
+
+	if opt.GamepadDeadzone == 0 {
+		opt.GamepadDeadzone = 0.1
+	}
+
+	if opt.Debug && opt.DebugLogger == nil {
+		opt.DebugLogger = func(m DebugMessage) { log.Println(m) }
+	}
+
+	if opt.LoopMode == LoopVSync {
+		opt.VSync = true
+	}
+
+	var replay *inputReplay
+	if opt.ReplayInput != nil {
+		var err error
+		if replay, err = readInputReplay(opt.ReplayInput); err != nil {
+			return err
+		}
+	}
+
 	var err error
 	mainthread.Init(func() {
 		var window *glfwWindow
@@ -124,17 +417,49 @@ func Main(opt Options, run func(App) error) error {
 		}()
 
 		a := app{
-			glfwWindow: window,
-			deltaTime:  1 / float64(opt.FrameRate),
-			eventch:    make(chan interface{}),
+			glfwWindow:   window,
+			deltaTime:    1 / float64(opt.FrameRate),
+			drawDelta:    window.refreshDeltaTime,
+			loopMode:     opt.LoopMode,
+			maxFrameSkip: opt.MaxFrameSkip,
+			eventch:      make(chan interface{}),
+			replay:       replay,
+		}
+
+		if opt.MaxFrameRate > 0 {
+			a.drawDelta = 1 / float64(opt.MaxFrameRate)
+		}
+
+		if opt.RecordInput != nil {
+			a.recorder = newInputRecorder(opt.RecordInput)
 		}
 
 		ctx, cancel := context.WithCancel(context.Background())
 		go func() {
+			gl.SetGoroutineChecking(opt.Debug)
 			err = run(&a)
 			cancel()
 		}()
 		a.loop(ctx)
+
+		// take over the GL context to release the remaining resources
+		gl.SetGoroutineChecking(opt.Debug)
+
+		if a.upscaler != nil {
+			a.upscaler.delete()
+		}
+		flushCalls()
+		if opt.Debug {
+			// unreachable resources that have not been finalized yet are not leaks
+			collectDeletes()
+		}
+		if m, ok := leakReport(); ok && opt.Debug {
+			opt.DebugLogger(m)
+		}
+
+		if err == nil && a.recorder != nil {
+			err = a.recorder.err
+		}
 	})
 
 	return err
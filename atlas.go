@@ -0,0 +1,255 @@
+package pancake
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+	"sort"
+)
+
+// AtlasOptions configures an Atlas.
+type AtlasOptions struct {
+	// PageSize is the size of the textures that images are packed into.
+	// Defaults to 1024x1024.
+	PageSize image.Point
+	// Padding is the number of transparent pixels between packed images.
+	Padding int
+	// Extrude is the number of times the edge pixels of each image are repeated
+	// around it, which prevents neighbouring images from bleeding in when filtering.
+	Extrude int
+	// TextureOptions determines how the pages are sampled.
+	TextureOptions TextureOptions
+}
+
+// Atlas packs many small images into a few large textures so that
+// sprites using them can be drawn in fewer draw calls.
+// Images can be added at any time. An image that does not fit in
+// any of the existing pages is packed into a new page.
+//
+//	atlas := pancake.NewAtlas(pancake.AtlasOptions{Padding: 1, Extrude: 1})
+//	images, err := atlas.AddAll(sprites)
+type Atlas struct {
+	opt   AtlasOptions
+	pages []*atlasPage
+}
+
+// NewAtlas creates an empty Atlas.
+func NewAtlas(opt AtlasOptions) *Atlas {
+	if opt.PageSize == (image.Point{}) {
+		opt.PageSize = image.Point{1024, 1024}
+	}
+	return &Atlas{opt: opt}
+}
+
+// Pages returns the textures that the images are packed into.
+func (a *Atlas) Pages() []*Texture {
+	pages := make([]*Texture, len(a.pages))
+	for i, p := range a.pages {
+		pages[i] = p.texture
+	}
+	return pages
+}
+
+// Add packs an image into the atlas and returns it as a sub-image of a page.
+// It returns an error if the image does not fit in an empty page.
+func (a *Atlas) Add(img image.Image) (Image, error) {
+	size := img.Bounds().Size()
+	if size.X <= 0 || size.Y <= 0 {
+		return nil, errors.New("image is empty")
+	}
+
+	border := a.opt.Extrude
+	cell := size.Add(image.Point{2*border + a.opt.Padding, 2*border + a.opt.Padding})
+	if cell.X > a.opt.PageSize.X || cell.Y > a.opt.PageSize.Y {
+		return nil, errors.New("image is larger than an atlas page")
+	}
+
+	var page *atlasPage
+	var at image.Point
+	for _, p := range a.pages {
+		if pos, ok := p.insert(cell); ok {
+			page, at = p, pos
+			break
+		}
+	}
+
+	if page == nil {
+		page = newAtlasPage(a.opt)
+		a.pages = append(a.pages, page)
+		at, _ = page.insert(cell)
+	}
+
+	page.texture.SetImage(extrude(img, border), at)
+	region := image.Rectangle{Min: at, Max: at.Add(size)}.Add(image.Point{border, border})
+	return page.texture.SubImage(region), nil
+}
+
+// AddAll packs a set of images into the atlas and returns them
+// as sub-images in the same order.
+// The images are packed from tallest to shortest, which packs
+// more tightly than adding them one by one.
+// If an error is returned the images added so far remain in the atlas.
+func (a *Atlas) AddAll(imgs []image.Image) ([]Image, error) {
+	order := make([]int, len(imgs))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		si, sj := imgs[order[i]].Bounds().Size(), imgs[order[j]].Bounds().Size()
+		if si.Y != sj.Y {
+			return si.Y > sj.Y
+		}
+		return si.X > sj.X
+	})
+
+	images := make([]Image, len(imgs))
+	for _, i := range order {
+		img, err := a.Add(imgs[i])
+		if err != nil {
+			return nil, err
+		}
+		images[i] = img
+	}
+
+	return images, nil
+}
+
+// Delete deletes the pages of the atlas.
+// Images returned by the atlas must no longer be used.
+func (a *Atlas) Delete() {
+	for _, p := range a.pages {
+		p.texture.Delete()
+	}
+	a.pages = nil
+}
+
+// extrude returns an RGBA copy of an image surrounded by n repeats of its edge pixels.
+func extrude(img image.Image, n int) *image.RGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	rgba := image.NewRGBA(image.Rect(0, 0, w+2*n, h+2*n))
+	draw.Draw(rgba, image.Rect(n, n, n+w, n+h), img, b.Min, draw.Src)
+	if n == 0 {
+		return rgba
+	}
+
+	// repeat the left and right columns
+	for y := n; y < n+h; y++ {
+		row := rgba.Pix[y*rgba.Stride:]
+		for x := 0; x < n; x++ {
+			copy(row[4*x:4*x+4], row[4*n:4*n+4])
+			copy(row[4*(n+w+x):4*(n+w+x)+4], row[4*(n+w-1):4*(n+w)])
+		}
+	}
+
+	// repeat the top and bottom rows including the corners
+	top := rgba.Pix[n*rgba.Stride : (n+1)*rgba.Stride]
+	bottom := rgba.Pix[(n+h-1)*rgba.Stride : (n+h)*rgba.Stride]
+	for y := 0; y < n; y++ {
+		copy(rgba.Pix[y*rgba.Stride:], top)
+		copy(rgba.Pix[(n+h+y)*rgba.Stride:], bottom)
+	}
+
+	return rgba
+}
+
+// skylineNode is a horizontal segment of the skyline.
+type skylineNode struct {
+	x, y, w int
+}
+
+// atlasPage packs rectangles into a texture using the skyline bottom-left algorithm.
+// The skyline is the top edge of the packed rectangles from left to right.
+type atlasPage struct {
+	texture *Texture
+	size    image.Point
+	skyline []skylineNode
+}
+
+func newAtlasPage(opt AtlasOptions) *atlasPage {
+	size := opt.PageSize
+	pixels := make([]byte, 4*size.X*size.Y)
+	return &atlasPage{
+		texture: NewTextureWithOptions(size, ColorFormatRGBA, pixels, opt.TextureOptions),
+		size:    size,
+		skyline: []skylineNode{{0, 0, size.X}},
+	}
+}
+
+// fit returns the lowest y at which a rectangle fits when its left edge
+// is at the start of node i.
+func (p *atlasPage) fit(i int, size image.Point) (int, bool) {
+	x := p.skyline[i].x
+	if x+size.X > p.size.X {
+		return 0, false
+	}
+
+	y := 0
+	for j, left := i, size.X; left > 0; j++ {
+		if p.skyline[j].y > y {
+			y = p.skyline[j].y
+		}
+		if y+size.Y > p.size.Y {
+			return 0, false
+		}
+		left -= p.skyline[j].w
+	}
+
+	return y, true
+}
+
+// insert finds the position that keeps the skyline lowest and
+// places a rectangle there.
+func (p *atlasPage) insert(size image.Point) (image.Point, bool) {
+	best, bestY, bestW := -1, 0, 0
+	for i, node := range p.skyline {
+		y, ok := p.fit(i, size)
+		if !ok {
+			continue
+		}
+		if best < 0 || y < bestY || (y == bestY && node.w < bestW) {
+			best, bestY, bestW = i, y, node.w
+		}
+	}
+
+	if best < 0 {
+		return image.Point{}, false
+	}
+
+	at := image.Point{p.skyline[best].x, bestY}
+	p.place(best, image.Rectangle{Min: at, Max: at.Add(size)})
+	return at, true
+}
+
+// place raises the skyline over a rectangle whose left edge is at the start of node i.
+func (p *atlasPage) place(i int, r image.Rectangle) {
+	node := skylineNode{r.Min.X, r.Max.Y, r.Dx()}
+	p.skyline = append(p.skyline, skylineNode{})
+	copy(p.skyline[i+1:], p.skyline[i:])
+	p.skyline[i] = node
+
+	// shrink or remove the nodes that are now covered by the rectangle
+	for j := i + 1; j < len(p.skyline); {
+		prev, next := p.skyline[j-1], &p.skyline[j]
+		overlap := prev.x + prev.w - next.x
+		if overlap <= 0 {
+			break
+		} else if overlap < next.w {
+			next.x += overlap
+			next.w -= overlap
+			break
+		}
+		p.skyline = append(p.skyline[:j], p.skyline[j+1:]...)
+	}
+
+	// merge neighbouring nodes at the same height
+	for j := 1; j < len(p.skyline); {
+		if p.skyline[j-1].y == p.skyline[j].y {
+			p.skyline[j-1].w += p.skyline[j].w
+			p.skyline = append(p.skyline[:j], p.skyline[j+1:]...)
+		} else {
+			j++
+		}
+	}
+}
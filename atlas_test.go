@@ -0,0 +1,95 @@
+package pancake
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/askeladdk/pancake/mathx"
+)
+
+func TestAtlasPagePacking(t *testing.T) {
+	page := &atlasPage{
+		size:    image.Point{64, 64},
+		skyline: []skylineNode{{0, 0, 64}},
+	}
+
+	var packed []image.Rectangle
+	for _, size := range []image.Point{
+		{30, 20}, {30, 10}, {10, 30}, {20, 20}, {64, 4}, {8, 8}, {8, 8}, {16, 2},
+	} {
+		at, ok := page.insert(size)
+		if !ok {
+			t.Fatal("no room for", size)
+		}
+		r := image.Rectangle{Min: at, Max: at.Add(size)}
+		if !r.In(image.Rectangle{Max: page.size}) {
+			t.Fatal("out of bounds", r)
+		}
+		for _, q := range packed {
+			if r.Overlaps(q) {
+				t.Fatal("overlap", r, q)
+			}
+		}
+		packed = append(packed, r)
+	}
+
+	if _, ok := page.insert(image.Point{65, 1}); ok {
+		t.Fatal("too wide")
+	}
+}
+
+func TestAtlas(t *testing.T) {
+	atlas := NewAtlas(AtlasOptions{
+		PageSize: image.Point{16, 16},
+		Padding:  1,
+		Extrude:  1,
+		TextureOptions: TextureOptions{
+			MinFilter: FilterNearest,
+			MagFilter: FilterNearest,
+		},
+	})
+	defer atlas.Delete()
+
+	red := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 2; x++ {
+			red.SetRGBA(x, y, color.RGBA{255, 0, 0, 255})
+		}
+	}
+	tall := image.NewRGBA(image.Rect(0, 0, 8, 13))
+
+	// the tall image is packed first and the red image beside it
+	images, err := atlas.AddAll([]image.Image{red, tall})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(atlas.Pages()) != 1 || images[0].Texture() != images[1].Texture() {
+		t.Fatal("pages", len(atlas.Pages()))
+	} else if images[0].Scale() != (mathx.Vec2{2, 2}) {
+		t.Fatal("scale", images[0].Scale())
+	}
+
+	// the red image and its extruded border are red and the padding is transparent
+	region := images[0].TextureRegion()
+	x, y := int(region.Tx*16), int(region.Ty*16)
+	pixels := images[0].Texture().Pixels(nil)
+	for _, p := range []image.Point{{x - 1, y - 1}, {x, y}, {x + 2, y + 2}} {
+		if i := 4 * (p.Y*16 + p.X); pixels[i] != 255 || pixels[i+3] != 255 {
+			t.Fatal("pixel", p, pixels[i:i+4])
+		}
+	}
+	if i := 4 * (y*16 + x + 3); pixels[i+3] != 0 {
+		t.Fatal("padding", pixels[i:i+4])
+	}
+
+	// an image that does not fit beside the others goes to a new page
+	if img, err := atlas.Add(image.NewRGBA(image.Rect(0, 0, 10, 10))); err != nil {
+		t.Fatal(err)
+	} else if len(atlas.Pages()) != 2 || img.Texture() != atlas.Pages()[1] {
+		t.Fatal("pages", len(atlas.Pages()))
+	}
+
+	if _, err := atlas.Add(image.NewRGBA(image.Rect(0, 0, 15, 15))); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -13,6 +13,7 @@ func newBinder(fn func(uint32)) *binder {
 }
 
 func (b *binder) bind(ref uint32) {
+	flushRequestedCalls()
 	b.stack = append(b.stack, b.cur)
 	if ref != b.cur {
 		b.bindfn(ref)
@@ -28,3 +29,23 @@ func (b *binder) unbind() {
 		b.cur = prev
 	}
 }
+
+// rebind binds the current ref again after it has been changed behind the binder's back.
+func (b *binder) rebind() {
+	b.bindfn(b.cur)
+}
+
+// forget resets the cached binding if ref has been deleted,
+// because GL reverts the binding of a deleted object to zero.
+// Bindings of ref further down the stack are reverted to zero as well
+// so that unbind does not bind the deleted object again.
+func (b *binder) forget(ref uint32) {
+	if b.cur == ref {
+		b.cur = 0
+	}
+	for i, r := range b.stack {
+		if r == ref {
+			b.stack[i] = 0
+		}
+	}
+}
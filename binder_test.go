@@ -0,0 +1,27 @@
+package pancake
+
+import "testing"
+
+func TestBinderForget(t *testing.T) {
+	var bound []uint32
+	b := newBinder(func(ref uint32) { bound = append(bound, ref) })
+
+	b.bind(1)
+	b.bind(2)
+	b.bind(1)
+	b.forget(1)
+	b.unbind()
+	b.unbind()
+	b.unbind()
+
+	// 1 is never bound again after it has been deleted
+	want := []uint32{1, 2, 1, 2, 0}
+	if len(bound) != len(want) {
+		t.Fatal(bound)
+	}
+	for i := range want {
+		if bound[i] != want[i] {
+			t.Fatal(bound)
+		}
+	}
+}
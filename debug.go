@@ -0,0 +1,173 @@
+package pancake
+
+import (
+	"fmt"
+
+	gl "github.com/askeladdk/pancake/opengl"
+)
+
+// DebugSource identifies the origin of a DebugMessage.
+type DebugSource int
+
+const (
+	DebugSourceOther DebugSource = iota
+	DebugSourceAPI
+	DebugSourceWindowSystem
+	DebugSourceShaderCompiler
+	DebugSourceThirdParty
+	DebugSourceApplication
+)
+
+func (s DebugSource) String() string {
+	switch s {
+	case DebugSourceAPI:
+		return "api"
+	case DebugSourceWindowSystem:
+		return "window system"
+	case DebugSourceShaderCompiler:
+		return "shader compiler"
+	case DebugSourceThirdParty:
+		return "third party"
+	case DebugSourceApplication:
+		return "application"
+	default:
+		return "other"
+	}
+}
+
+// DebugType classifies a DebugMessage.
+type DebugType int
+
+const (
+	DebugTypeOther DebugType = iota
+	DebugTypeError
+	DebugTypeDeprecatedBehavior
+	DebugTypeUndefinedBehavior
+	DebugTypePortability
+	DebugTypePerformance
+	DebugTypeMarker
+	DebugTypePushGroup
+	DebugTypePopGroup
+)
+
+func (t DebugType) String() string {
+	switch t {
+	case DebugTypeError:
+		return "error"
+	case DebugTypeDeprecatedBehavior:
+		return "deprecated behavior"
+	case DebugTypeUndefinedBehavior:
+		return "undefined behavior"
+	case DebugTypePortability:
+		return "portability"
+	case DebugTypePerformance:
+		return "performance"
+	case DebugTypeMarker:
+		return "marker"
+	case DebugTypePushGroup:
+		return "push group"
+	case DebugTypePopGroup:
+		return "pop group"
+	default:
+		return "other"
+	}
+}
+
+// DebugSeverity is the importance of a DebugMessage.
+type DebugSeverity int
+
+const (
+	DebugSeverityNotification DebugSeverity = iota
+	DebugSeverityLow
+	DebugSeverityMedium
+	DebugSeverityHigh
+)
+
+func (s DebugSeverity) String() string {
+	switch s {
+	case DebugSeverityLow:
+		return "low"
+	case DebugSeverityMedium:
+		return "medium"
+	case DebugSeverityHigh:
+		return "high"
+	default:
+		return "notification"
+	}
+}
+
+// DebugMessage is a message reported by the GL debug output.
+type DebugMessage struct {
+	Source   DebugSource
+	Type     DebugType
+	Severity DebugSeverity
+	ID       uint32
+	Message  string
+}
+
+func (m DebugMessage) String() string {
+	return fmt.Sprintf("gl %s %s [%s] %d: %s", m.Source, m.Type, m.Severity, m.ID, m.Message)
+}
+
+func newDebugMessage(source, xtype gl.Enum, id uint32, severity gl.Enum, message string) DebugMessage {
+	m := DebugMessage{ID: id, Message: message}
+
+	switch source {
+	case gl.DEBUG_SOURCE_API:
+		m.Source = DebugSourceAPI
+	case gl.DEBUG_SOURCE_WINDOW_SYSTEM:
+		m.Source = DebugSourceWindowSystem
+	case gl.DEBUG_SOURCE_SHADER_COMPILER:
+		m.Source = DebugSourceShaderCompiler
+	case gl.DEBUG_SOURCE_THIRD_PARTY:
+		m.Source = DebugSourceThirdParty
+	case gl.DEBUG_SOURCE_APPLICATION:
+		m.Source = DebugSourceApplication
+	}
+
+	switch xtype {
+	case gl.DEBUG_TYPE_ERROR:
+		m.Type = DebugTypeError
+	case gl.DEBUG_TYPE_DEPRECATED_BEHAVIOR:
+		m.Type = DebugTypeDeprecatedBehavior
+	case gl.DEBUG_TYPE_UNDEFINED_BEHAVIOR:
+		m.Type = DebugTypeUndefinedBehavior
+	case gl.DEBUG_TYPE_PORTABILITY:
+		m.Type = DebugTypePortability
+	case gl.DEBUG_TYPE_PERFORMANCE:
+		m.Type = DebugTypePerformance
+	case gl.DEBUG_TYPE_MARKER:
+		m.Type = DebugTypeMarker
+	case gl.DEBUG_TYPE_PUSH_GROUP:
+		m.Type = DebugTypePushGroup
+	case gl.DEBUG_TYPE_POP_GROUP:
+		m.Type = DebugTypePopGroup
+	}
+
+	switch severity {
+	case gl.DEBUG_SEVERITY_LOW:
+		m.Severity = DebugSeverityLow
+	case gl.DEBUG_SEVERITY_MEDIUM:
+		m.Severity = DebugSeverityMedium
+	case gl.DEBUG_SEVERITY_HIGH:
+		m.Severity = DebugSeverityHigh
+	}
+
+	return m
+}
+
+// enableDebugOutput turns on error checking and routes debug messages to logger.
+// It logs a single message if the context does not support debug output.
+func enableDebugOutput(logger func(DebugMessage)) {
+	gl.SetErrorChecking(true)
+	if !gl.DebugMessageCallback(func(source, xtype gl.Enum, id uint32, severity gl.Enum, message string) {
+		logger(newDebugMessage(source, xtype, id, severity, message))
+	}) {
+		logger(DebugMessage{
+			Source:   DebugSourceApplication,
+			Type:     DebugTypeOther,
+			Severity: DebugSeverityMedium,
+			Message:  "debug output is not supported by the GL context, only GL errors are checked",
+		})
+	}
+}
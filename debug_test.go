@@ -0,0 +1,38 @@
+package pancake
+
+import (
+	"testing"
+
+	gl "github.com/askeladdk/pancake/opengl"
+)
+
+func TestNewDebugMessage(t *testing.T) {
+	for _, tc := range []struct {
+		source, xtype, severity gl.Enum
+		want                    DebugMessage
+	}{
+		{gl.DEBUG_SOURCE_API, gl.DEBUG_TYPE_ERROR, gl.DEBUG_SEVERITY_HIGH,
+			DebugMessage{Source: DebugSourceAPI, Type: DebugTypeError, Severity: DebugSeverityHigh}},
+		{gl.DEBUG_SOURCE_WINDOW_SYSTEM, gl.DEBUG_TYPE_DEPRECATED_BEHAVIOR, gl.DEBUG_SEVERITY_MEDIUM,
+			DebugMessage{Source: DebugSourceWindowSystem, Type: DebugTypeDeprecatedBehavior, Severity: DebugSeverityMedium}},
+		{gl.DEBUG_SOURCE_SHADER_COMPILER, gl.DEBUG_TYPE_UNDEFINED_BEHAVIOR, gl.DEBUG_SEVERITY_LOW,
+			DebugMessage{Source: DebugSourceShaderCompiler, Type: DebugTypeUndefinedBehavior, Severity: DebugSeverityLow}},
+		{gl.DEBUG_SOURCE_THIRD_PARTY, gl.DEBUG_TYPE_PORTABILITY, gl.DEBUG_SEVERITY_NOTIFICATION,
+			DebugMessage{Source: DebugSourceThirdParty, Type: DebugTypePortability, Severity: DebugSeverityNotification}},
+		{gl.DEBUG_SOURCE_APPLICATION, gl.DEBUG_TYPE_PERFORMANCE, gl.DEBUG_SEVERITY_HIGH,
+			DebugMessage{Source: DebugSourceApplication, Type: DebugTypePerformance, Severity: DebugSeverityHigh}},
+		{gl.DEBUG_SOURCE_OTHER, gl.DEBUG_TYPE_MARKER, gl.DEBUG_SEVERITY_NOTIFICATION,
+			DebugMessage{Source: DebugSourceOther, Type: DebugTypeMarker, Severity: DebugSeverityNotification}},
+		{gl.DEBUG_SOURCE_API, gl.DEBUG_TYPE_PUSH_GROUP, gl.DEBUG_SEVERITY_NOTIFICATION,
+			DebugMessage{Source: DebugSourceAPI, Type: DebugTypePushGroup, Severity: DebugSeverityNotification}},
+		{gl.DEBUG_SOURCE_API, gl.DEBUG_TYPE_POP_GROUP, gl.DEBUG_SEVERITY_NOTIFICATION,
+			DebugMessage{Source: DebugSourceAPI, Type: DebugTypePopGroup, Severity: DebugSeverityNotification}},
+		{gl.DEBUG_SOURCE_API, gl.DEBUG_TYPE_OTHER, gl.DEBUG_SEVERITY_NOTIFICATION,
+			DebugMessage{Source: DebugSourceAPI, Type: DebugTypeOther, Severity: DebugSeverityNotification}},
+	} {
+		tc.want.ID, tc.want.Message = 7, "message"
+		if got := newDebugMessage(tc.source, tc.xtype, 7, tc.severity, "message"); got != tc.want {
+			t.Errorf("got %v, want %v", got, tc.want)
+		}
+	}
+}
@@ -33,13 +33,29 @@ func (ebo *IndexBuffer) Len() int {
 func (ebo *IndexBuffer) Draw(mode gl.Enum, i, j int) {
 	if j > i && i >= 0 && j <= ebo.count {
 		gl.DrawElements(mode, j-i, ebo.xtype, i)
+		countDraw(j - i)
 		return
 	}
 	panic(errors.New("range out of bounds"))
 }
 
+// Delete deletes the IndexBuffer. It is safe to call Delete more than once.
+func (ebo *IndexBuffer) Delete() {
+	if ebo.id != 0 {
+		runtime.SetFinalizer(ebo, nil)
+		ebo.delete()
+	}
+}
+
 func (ebo *IndexBuffer) delete() {
+	ebobinder.forget(uint32(ebo.id))
+	untrackObject("buffer", uint32(ebo.id))
 	gl.DeleteBuffer(ebo.id)
+	ebo.id = 0
+}
+
+func (ebo *IndexBuffer) finalize() {
+	queueCall(ebo.delete)
 }
 
 func newIndexBuffer(count, stride int, xtype gl.Enum, ptr unsafe.Pointer) *IndexBuffer {
@@ -49,7 +65,8 @@ func newIndexBuffer(count, stride int, xtype gl.Enum, ptr unsafe.Pointer) *Index
 		id:    gl.CreateBuffer(),
 	}
 
-	runtime.SetFinalizer(ebo, (*IndexBuffer).delete)
+	trackObject("buffer", uint32(ebo.id))
+	runtime.SetFinalizer(ebo, (*IndexBuffer).finalize)
 
 	ebo.Begin()
 	defer ebo.End()
@@ -33,8 +33,22 @@ func (vao *ivarray) slice(i, j int) *IndexedVertexArraySlice {
 	panic(fmt.Errorf("range out of bounds"))
 }
 
+func (vao *ivarray) Delete() {
+	if vao.id != 0 {
+		runtime.SetFinalizer(vao, nil)
+		vao.delete()
+	}
+}
+
 func (vao *ivarray) delete() {
+	vaobinder.forget(uint32(vao.id))
+	untrackObject("vertex array", uint32(vao.id))
 	gl.DeleteVertexArray(vao.id)
+	vao.id = 0
+}
+
+func (vao *ivarray) finalize() {
+	queueCall(vao.delete)
 }
 
 type IndexedVertexArraySlice struct {
@@ -42,6 +56,12 @@ type IndexedVertexArraySlice struct {
 	i, j int
 }
 
+// Delete deletes the vertex array shared by all slices of it.
+// The VertexBuffer and IndexBuffer are not deleted.
+func (ivas *IndexedVertexArraySlice) Delete() {
+	ivas.vao.Delete()
+}
+
 func (ivas *IndexedVertexArraySlice) Begin() {
 	ivas.vao.begin()
 }
@@ -76,7 +96,8 @@ func NewIndexedVertexArraySlice(ebo *IndexBuffer, vbo *VertexBuffer) *IndexedVer
 		id:  gl.CreateVertexArray(),
 	}
 
-	runtime.SetFinalizer(iva, (*ivarray).delete)
+	trackObject("vertex array", uint32(iva.id))
+	runtime.SetFinalizer(iva, (*ivarray).finalize)
 
 	iva.begin()
 	defer iva.end()
@@ -26,6 +26,44 @@ type MouseMoveEvent struct {
 	Position image.Point
 }
 
+type ScrollEvent struct {
+	X, Y     float64
+	Position image.Point
+}
+
+type CursorEnterEvent struct {
+	Entered bool
+}
+
+type FocusEvent struct {
+	Focused bool
+}
+
+type IconifyEvent struct {
+	Iconified bool
+}
+
+type GamepadConnectEvent struct {
+	Gamepad Gamepad
+	Name    string
+}
+
+type GamepadDisconnectEvent struct {
+	Gamepad Gamepad
+}
+
+type GamepadButtonEvent struct {
+	Modifiers
+	Gamepad Gamepad
+	Button  GamepadButton
+}
+
+type GamepadAxisEvent struct {
+	Gamepad Gamepad
+	Axis    GamepadAxis
+	Value   float64
+}
+
 type FrameEvent struct {
 	DeltaTime float64
 }
@@ -33,3 +71,13 @@ type FrameEvent struct {
 type DrawEvent struct {
 	Alpha float64
 }
+
+// ResizeEvent is emitted when the framebuffer of the window is resized.
+type ResizeEvent struct {
+	// Size is the size of the framebuffer in pixels.
+	Size image.Point
+	// Viewport is the recomputed viewport in framebuffer pixels.
+	Viewport image.Rectangle
+	// Bounds is the logical resolution, which changes in ScalingExpand mode.
+	Bounds image.Rectangle
+}
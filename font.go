@@ -127,6 +127,11 @@ func (fnt *Font) Texture() *Texture {
 	return fnt.texture
 }
 
+// Delete deletes the glyph atlas Texture.
+func (fnt *Font) Delete() {
+	fnt.texture.Delete()
+}
+
 // Glyph returns the glyph associated with a rune.
 func (fnt *Font) Glyph(r rune) Glyph {
 	if glyph, ok := fnt.mapping[r]; ok {
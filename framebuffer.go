@@ -2,6 +2,7 @@ package pancake
 
 import (
 	"errors"
+	"fmt"
 	"image"
 	"runtime"
 
@@ -12,11 +13,21 @@ var fbobinder = newBinder(func(id uint32) {
 	gl.BindFramebuffer(gl.FRAMEBUFFER, gl.Framebuffer(id))
 })
 
+// MaxColorAttachments is the minimum number of color attachments
+// of a Framebuffer according to the spec.
+const MaxColorAttachments = 8
+
 type Framebuffer struct {
-	color   *Texture
-	depth   *renderbuffer
-	stencil *renderbuffer
-	id      gl.Framebuffer
+	size         image.Point
+	samples      int
+	colors       []*Texture
+	colorBuffers []*renderbuffer
+	drawBuffers  []gl.Enum
+	depthTex     *Texture
+	depth        *renderbuffer
+	stencil      *renderbuffer
+	id           gl.Framebuffer
+	owned        []*Texture
 }
 
 func (fbo *Framebuffer) Begin() {
@@ -28,52 +39,347 @@ func (fbo *Framebuffer) End() {
 }
 
 func (fbo *Framebuffer) Bounds() image.Rectangle {
-	return image.Rectangle{image.Point{}, fbo.color.Size()}
+	return image.Rectangle{image.Point{}, fbo.size}
+}
+
+// Samples reports the number of samples per pixel, or zero if the Framebuffer is not multisampled.
+func (fbo *Framebuffer) Samples() int {
+	return fbo.samples
 }
 
+// Color returns the first color attachment, or nil if there is none.
 func (fbo *Framebuffer) Color() *Texture {
-	return fbo.color
+	return fbo.ColorAt(0)
+}
+
+// ColorAt returns the i-th color attachment, or nil if there is none.
+// Fragment shader output location i is written to the i-th color attachment.
+// The color attachments of a multisampled Framebuffer are not textures
+// and must be resolved first.
+func (fbo *Framebuffer) ColorAt(i int) *Texture {
+	if i < len(fbo.colors) {
+		return fbo.colors[i]
+	}
+	return nil
+}
+
+// NumColors reports the number of color attachments.
+func (fbo *Framebuffer) NumColors() int {
+	return len(fbo.drawBuffers)
+}
+
+// DepthTexture returns the depth attachment if it is a Texture, or nil otherwise.
+func (fbo *Framebuffer) DepthTexture() *Texture {
+	return fbo.depthTex
+}
+
+// Image reads back the first color attachment.
+func (fbo *Framebuffer) Image() *image.RGBA {
+	fbo.Begin()
+	defer fbo.End()
+	return readPixels(fbo.Bounds())
 }
 
 func (src *Framebuffer) BlitTo(dst *Framebuffer, sr, dr image.Rectangle, mask gl.Enum, filter TextureFilter) {
 	gl.BlitNamedFramebuffer(src.id, dst.id, sr, dr, mask, filter.param())
+	fbobinder.rebind()
+}
+
+// Resolve resolves the samples of a multisampled Framebuffer into dst.
+// Every color attachment is resolved into the color attachment of dst at the same index,
+// and the depth and stencil buffers are resolved if both framebuffers have them.
+// The framebuffers must be of the same size.
+func (fbo *Framebuffer) Resolve(dst *Framebuffer) {
+	if fbo.size != dst.size {
+		panic(errors.New("framebuffer sizes do not match"))
+	} else if fbo.NumColors() > dst.NumColors() {
+		panic(errors.New("destination framebuffer has too few color attachments"))
+	}
+
+	r := fbo.Bounds()
+	for i, attachment := range fbo.drawBuffers {
+		fbo.Begin()
+		gl.ReadBuffer(attachment)
+		fbo.End()
+		dst.Begin()
+		gl.DrawBuffers(dst.drawBuffers[i : i+1])
+		dst.End()
+		fbo.BlitTo(dst, r, r, gl.COLOR_BUFFER_BIT, FilterNearest)
+	}
+
+	var mask gl.Enum
+	if fbo.hasDepth() && dst.hasDepth() {
+		mask |= gl.DEPTH_BUFFER_BIT
+	}
+	if fbo.stencil != nil && (dst.stencil != nil || dst.depthTex != nil && dst.depthTex.format == ColorFormatDepthStencil) {
+		mask |= gl.STENCIL_BUFFER_BIT
+	}
+	if mask != 0 {
+		fbo.BlitTo(dst, r, r, mask, FilterNearest)
+	}
+
+	// restore the read and draw buffers
+	if len(fbo.drawBuffers) > 0 {
+		fbo.Begin()
+		gl.ReadBuffer(fbo.drawBuffers[0])
+		fbo.End()
+	}
+	dst.Begin()
+	gl.DrawBuffers(dst.drawBuffers)
+	dst.End()
+}
+
+func (fbo *Framebuffer) hasDepth() bool {
+	return fbo.depth != nil || fbo.depthTex != nil
+}
+
+// Delete deletes the Framebuffer and its depth and stencil buffers.
+// Textures are only deleted if they were created by the Framebuffer.
+// It is safe to call Delete more than once.
+func (fbo *Framebuffer) Delete() {
+	if fbo.id != 0 {
+		runtime.SetFinalizer(fbo, nil)
+		fbo.delete()
+		if fbo.depth != nil {
+			fbo.depth.Delete()
+		}
+		if fbo.stencil != nil {
+			fbo.stencil.Delete()
+		}
+		for _, rbo := range fbo.colorBuffers {
+			rbo.Delete()
+		}
+		for _, tex := range fbo.owned {
+			tex.Delete()
+		}
+	}
 }
 
 func (fbo *Framebuffer) delete() {
+	fbobinder.forget(uint32(fbo.id))
+	untrackObject("framebuffer", uint32(fbo.id))
 	gl.DeleteFramebuffer(fbo.id)
+	fbo.id = 0
 }
 
-func NewFramebufferFromTexture(color *Texture, depthStencil bool) (*Framebuffer, error) {
-	if color.ColorFormat() != ColorFormatRGB && color.ColorFormat() != ColorFormatRGBA {
-		return nil, errors.New("color texture must be in RGB(A) color format")
+func (fbo *Framebuffer) finalize() {
+	queueCall(fbo.delete)
+}
+
+// readPixels reads a rectangle of the currently bound framebuffer.
+// The rows are flipped so that the image is top-down.
+func readPixels(r image.Rectangle) *image.RGBA {
+	rgba := image.NewRGBA(image.Rectangle{Max: r.Size()})
+	packAlignment(ColorFormatRGBA, r.Dx())
+	gl.ReadPixels(r, gl.RGBA, gl.UNSIGNED_BYTE, rgba.Pix)
+
+	stride := rgba.Stride
+	tmp := make([]byte, stride)
+	for y0, y1 := 0, rgba.Rect.Dy()-1; y0 < y1; y0, y1 = y0+1, y1-1 {
+		row0 := rgba.Pix[y0*stride : (y0+1)*stride]
+		row1 := rgba.Pix[y1*stride : (y1+1)*stride]
+		copy(tmp, row0)
+		copy(row0, row1)
+		copy(row1, tmp)
+	}
+
+	return rgba
+}
+
+// FramebufferBuilder configures the attachments of a Framebuffer.
+// All attachments have the same size.
+//
+//	gbuffer, err := pancake.NewFramebufferBuilder(size).
+//		Color(pancake.ColorFormatRGBA, pancake.FilterNearest).
+//		Color(pancake.ColorFormatRGBA16F, pancake.FilterNearest).
+//		DepthTexture(pancake.FilterNearest, false).
+//		Build()
+type FramebufferBuilder struct {
+	size         image.Point
+	colors       []*Texture
+	colorFormats []ColorFormat
+	colorFilters []TextureFilter
+	depth        bool
+	depthTexture bool
+	depthFilter  TextureFilter
+	stencil      bool
+	samples      int
+	err          error
+}
+
+// NewFramebufferBuilder creates a FramebufferBuilder for framebuffers of the given size.
+func NewFramebufferBuilder(size image.Point) *FramebufferBuilder {
+	return &FramebufferBuilder{size: size}
+}
+
+func (b *FramebufferBuilder) fail(err error) *FramebufferBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+func (b *FramebufferBuilder) addColor(tex *Texture, format ColorFormat, filter TextureFilter) *FramebufferBuilder {
+	if len(b.colors) == MaxColorAttachments {
+		return b.fail(errors.New("too many color attachments"))
+	} else if format.depth() {
+		return b.fail(errors.New("color attachment must not be in a depth format"))
+	}
+	b.colors = append(b.colors, tex)
+	b.colorFormats = append(b.colorFormats, format)
+	b.colorFilters = append(b.colorFilters, filter)
+	return b
+}
+
+// Color adds a color attachment that is created by the Framebuffer.
+func (b *FramebufferBuilder) Color(format ColorFormat, filter TextureFilter) *FramebufferBuilder {
+	return b.addColor(nil, format, filter)
+}
+
+// ColorTexture adds an existing Texture as a color attachment.
+// It is not deleted with the Framebuffer.
+func (b *FramebufferBuilder) ColorTexture(tex *Texture) *FramebufferBuilder {
+	if tex.Size() != b.size {
+		return b.fail(errors.New("color texture size does not match framebuffer size"))
+	}
+	return b.addColor(tex, tex.ColorFormat(), 0)
+}
+
+// Depth adds a depth renderbuffer, optionally with a stencil buffer.
+func (b *FramebufferBuilder) Depth(stencil bool) *FramebufferBuilder {
+	if b.depth {
+		return b.fail(errors.New("framebuffer already has a depth attachment"))
+	}
+	b.depth, b.stencil = true, stencil
+	return b
+}
+
+// DepthTexture adds a depth attachment that can be sampled,
+// optionally with a stencil buffer.
+func (b *FramebufferBuilder) DepthTexture(filter TextureFilter, stencil bool) *FramebufferBuilder {
+	if b.depth {
+		return b.fail(errors.New("framebuffer already has a depth attachment"))
+	}
+	b.depth, b.depthTexture, b.depthFilter, b.stencil = true, true, filter, stencil
+	return b
+}
+
+// Samples makes the Framebuffer multisampled with n samples per pixel.
+// The attachments of a multisampled Framebuffer are renderbuffers that cannot be
+// sampled, so ColorTexture and DepthTexture cannot be used. Call Resolve to
+// resolve it into a Framebuffer that is not multisampled.
+func (b *FramebufferBuilder) Samples(n int) *FramebufferBuilder {
+	if n < 0 {
+		return b.fail(errors.New("sample count must not be negative"))
+	}
+	b.samples = n
+	return b
+}
+
+// Build creates the Framebuffer.
+// It returns a *GLError if the combination of attachments is not supported.
+func (b *FramebufferBuilder) Build() (*Framebuffer, error) {
+	if b.err != nil {
+		return nil, b.err
+	} else if len(b.colors) == 0 && !b.depthTexture {
+		return nil, errors.New("framebuffer has no attachments that can be read")
+	} else if b.size.X <= 0 || b.size.Y <= 0 {
+		return nil, errors.New("framebuffer size must be positive")
+	}
+
+	if b.samples > 0 {
+		if b.depthTexture {
+			return nil, errors.New("multisampled framebuffer cannot have a depth texture")
+		} else if max := gl.GetInteger(gl.MAX_SAMPLES); b.samples > max {
+			return nil, fmt.Errorf("sample count exceeds the maximum of %d", max)
+		}
+		for _, tex := range b.colors {
+			if tex != nil {
+				return nil, errors.New("multisampled framebuffer cannot have a color texture")
+			}
+		}
 	}
 
 	fbo := &Framebuffer{
-		id:    gl.CreateFramebuffer(),
-		color: color,
+		size:    b.size,
+		samples: b.samples,
+		id:      gl.CreateFramebuffer(),
 	}
 
-	runtime.SetFinalizer(fbo, (*Framebuffer).delete)
+	trackObject("framebuffer", uint32(fbo.id))
+	runtime.SetFinalizer(fbo, (*Framebuffer).finalize)
 
 	fbo.Begin()
 	defer fbo.End()
 
-	gl.FramebufferTexture2D(gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, fbo.color.id, 0)
+	for i, tex := range b.colors {
+		attachment := gl.Enum(gl.COLOR_ATTACHMENT0 + i)
+		if b.samples > 0 {
+			rbo := newRenderbuffer(b.size, b.colorFormats[i].internalFormat(), b.samples)
+			gl.FramebufferRenderbuffer(attachment, rbo.id)
+			fbo.colorBuffers = append(fbo.colorBuffers, rbo)
+		} else {
+			if tex == nil {
+				tex = NewTexture(b.size, b.colorFilters[i], b.colorFormats[i], nil)
+				fbo.owned = append(fbo.owned, tex)
+			}
+			gl.FramebufferTexture2D(attachment, gl.TEXTURE_2D, tex.id, 0)
+			fbo.colors = append(fbo.colors, tex)
+		}
+		fbo.drawBuffers = append(fbo.drawBuffers, attachment)
+	}
+	gl.DrawBuffers(fbo.drawBuffers)
+	if len(fbo.drawBuffers) == 0 {
+		gl.ReadBuffer(gl.NONE)
+	}
+
+	if b.depth {
+		attachment, format, internalFormat := gl.Enum(gl.DEPTH_ATTACHMENT), ColorFormatDepth, gl.Enum(gl.DEPTH_COMPONENT24)
+		if b.stencil {
+			attachment, format, internalFormat = gl.DEPTH_STENCIL_ATTACHMENT, ColorFormatDepthStencil, gl.DEPTH24_STENCIL8
+		}
 
-	if depthStencil {
-		depthStencilBuffer := newRenderbuffer(color.size, gl.DEPTH24_STENCIL8, 0)
-		gl.FramebufferRenderbuffer(gl.DEPTH_STENCIL_ATTACHMENT, depthStencilBuffer.id)
-		fbo.depth = depthStencilBuffer
-		fbo.stencil = depthStencilBuffer
+		if b.depthTexture {
+			fbo.depthTex = NewTexture(b.size, b.depthFilter, format, nil)
+			fbo.owned = append(fbo.owned, fbo.depthTex)
+			gl.FramebufferTexture2D(attachment, gl.TEXTURE_2D, fbo.depthTex.id, 0)
+		} else {
+			fbo.depth = newRenderbuffer(b.size, internalFormat, b.samples)
+			gl.FramebufferRenderbuffer(attachment, fbo.depth.id)
+			if b.stencil {
+				fbo.stencil = fbo.depth
+			}
+		}
 	}
 
 	if code := gl.CheckFramebufferStatus(); code != gl.FRAMEBUFFER_COMPLETE {
-		return nil, errors.New(errorToString(code))
+		fbo.Delete()
+		return nil, &GLError{Op: "CheckFramebufferStatus", Code: code}
 	}
 	return fbo, nil
 }
 
+func NewFramebufferFromTexture(color *Texture, depthStencil bool) (*Framebuffer, error) {
+	b := NewFramebufferBuilder(color.Size()).ColorTexture(color)
+	if depthStencil {
+		b.Depth(true)
+	}
+	return b.Build()
+}
+
+// NewMultisampleFramebuffer creates a multisampled Framebuffer with an RGBA color attachment.
+func NewMultisampleFramebuffer(size image.Point, samples int, depthStencil bool) (*Framebuffer, error) {
+	b := NewFramebufferBuilder(size).Color(ColorFormatRGBA, FilterNearest).Samples(samples)
+	if depthStencil {
+		b.Depth(true)
+	}
+	return b.Build()
+}
+
 func NewFramebuffer(size image.Point, filter TextureFilter, depthStencil bool) (*Framebuffer, error) {
-	color := NewTexture(size, filter, ColorFormatRGBA, nil)
-	return NewFramebufferFromTexture(color, depthStencil)
+	b := NewFramebufferBuilder(size).Color(ColorFormatRGBA, filter)
+	if depthStencil {
+		b.Depth(true)
+	}
+	return b.Build()
 }
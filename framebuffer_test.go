@@ -0,0 +1,74 @@
+package pancake
+
+import (
+	"image"
+	"testing"
+
+	gl "github.com/askeladdk/pancake/opengl"
+)
+
+func TestFramebufferBuilder(t *testing.T) {
+	size := image.Point{16, 16}
+
+	fbo, err := NewFramebufferBuilder(size).
+		Color(ColorFormatRGBA, FilterNearest).
+		Color(ColorFormatRGBA16F, FilterNearest).
+		Color(ColorFormatR32F, FilterNearest).
+		DepthTexture(FilterNearest, true).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fbo.Delete()
+
+	if fbo.NumColors() != 3 || fbo.ColorAt(1).ColorFormat() != ColorFormatRGBA16F {
+		t.Fatal(fbo.NumColors())
+	} else if fbo.DepthTexture() == nil || fbo.DepthTexture().ColorFormat() != ColorFormatDepthStencil {
+		t.Fatal("depth texture")
+	}
+
+	for _, b := range []*FramebufferBuilder{
+		NewFramebufferBuilder(size),
+		NewFramebufferBuilder(size).Color(ColorFormatDepth, FilterNearest),
+		NewFramebufferBuilder(size).Color(ColorFormatRGBA, FilterNearest).Depth(false).Depth(true),
+		NewFramebufferBuilder(size).ColorTexture(fbo.Color()).ColorTexture(NewTexture(image.Point{8, 8}, FilterNearest, ColorFormatRGBA, nil)),
+	} {
+		if _, err := b.Build(); err == nil {
+			t.Fatal("expected an error")
+		}
+	}
+}
+
+func TestFramebufferResolve(t *testing.T) {
+	size := image.Point{8, 8}
+
+	msaa, err := NewMultisampleFramebuffer(size, 4, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer msaa.Delete()
+
+	dst, err := NewFramebuffer(size, FilterNearest, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Delete()
+
+	if msaa.Samples() != 4 || msaa.Color() != nil || msaa.NumColors() != 1 {
+		t.Fatal(msaa.Samples(), msaa.NumColors())
+	}
+
+	msaa.Begin()
+	gl.ClearColor(1, 0, 0, 1)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+	msaa.End()
+
+	msaa.Resolve(dst)
+	if c := dst.Image().RGBAAt(4, 4); c.R != 255 || c.A != 255 {
+		t.Fatal(c)
+	}
+
+	if _, err := NewFramebufferBuilder(size).Samples(4).DepthTexture(FilterNearest, false).Build(); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,114 @@
+package pancake
+
+import (
+	"errors"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"golang.design/x/mainthread"
+)
+
+var errInvalidGamepadMappings = errors.New("invalid gamepad mappings")
+
+type gamepadState struct {
+	connected bool
+	state     glfw.GamepadState
+	// axes are the axis values after applying the deadzone.
+	axes [len(glfw.GamepadState{}.Axes)]float32
+}
+
+// connect marks the gamepad as connected and at rest,
+// so that only the buttons and axes that are in use emit events when it connects.
+func (gs *gamepadState) connect() {
+	*gs = gamepadState{connected: true}
+	for _, axis := range []GamepadAxis{GamepadAxisLeftTrigger, GamepadAxisRightTrigger} {
+		gs.state.Axes[axis] = -1
+		gs.axes[axis] = -1
+	}
+}
+
+// applyDeadzone snaps an axis value that is within deadzone of its rest position to rest.
+// Sticks rest at zero and triggers rest at -1.
+func applyDeadzone(axis GamepadAxis, value, deadzone float32) float32 {
+	switch axis {
+	case GamepadAxisLeftTrigger, GamepadAxisRightTrigger:
+		if value < -1+deadzone {
+			return -1
+		}
+	default:
+		if value > -deadzone && value < deadzone {
+			return 0
+		}
+	}
+	return value
+}
+
+// UpdateGamepadMappings adds or updates gamepad mappings in the
+// SDL_GameControllerDB format, one mapping per line.
+// It must be called after Main has been started.
+func UpdateGamepadMappings(mappings string) error {
+	var ok bool
+	mainthread.Call(func() {
+		ok = glfw.UpdateGamepadMappings(mappings)
+	})
+	if !ok {
+		return errInvalidGamepadMappings
+	}
+	return nil
+}
+
+// pollGamepads compares the state of every gamepad with that of the previous frame
+// and emits events for all changes. Must be called from the main thread.
+func (wnd *glfwWindow) pollGamepads() {
+	for i := range wnd.gamepads {
+		gamepad := Gamepad(i)
+		joystick := glfw.Joystick(i)
+		prev := &wnd.gamepads[i]
+
+		if !joystick.IsGamepad() {
+			if prev.connected {
+				*prev = gamepadState{}
+				wnd.inputEvents = append(wnd.inputEvents, GamepadDisconnectEvent{
+					Gamepad: gamepad,
+				})
+			}
+			continue
+		}
+
+		state := joystick.GetGamepadState()
+		if state == nil {
+			continue
+		}
+
+		if !prev.connected {
+			prev.connect()
+			wnd.inputEvents = append(wnd.inputEvents, GamepadConnectEvent{
+				Gamepad: gamepad,
+				Name:    joystick.GetGamepadName(),
+			})
+		}
+
+		for button, action := range state.Buttons {
+			if action != prev.state.Buttons[button] {
+				wnd.inputEvents = append(wnd.inputEvents, GamepadButtonEvent{
+					Modifiers: glfwModifiers(action, 0),
+					Gamepad:   gamepad,
+					Button:    GamepadButton(button),
+				})
+			}
+		}
+
+		for axis, value := range state.Axes {
+			value = applyDeadzone(GamepadAxis(axis), value, wnd.gamepadDeadzone)
+			if value != prev.axes[axis] {
+				prev.axes[axis] = value
+				wnd.inputEvents = append(wnd.inputEvents, GamepadAxisEvent{
+					Gamepad: gamepad,
+					Axis:    GamepadAxis(axis),
+					Value:   float64(value),
+				})
+			}
+		}
+
+		prev.state = *state
+	}
+}
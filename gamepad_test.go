@@ -0,0 +1,43 @@
+package pancake
+
+import "testing"
+
+func TestApplyDeadzone(t *testing.T) {
+	for _, tc := range []struct {
+		axis     GamepadAxis
+		value    float32
+		deadzone float32
+		want     float32
+	}{
+		{GamepadAxisLeftX, 0.05, 0.1, 0},
+		{GamepadAxisLeftY, -0.05, 0.1, 0},
+		{GamepadAxisRightX, 0.5, 0.1, 0.5},
+		{GamepadAxisRightY, -0.1, 0.1, -0.1},
+		{GamepadAxisLeftX, 0.05, -1, 0.05},
+		{GamepadAxisLeftTrigger, -0.95, 0.1, -1},
+		{GamepadAxisRightTrigger, -0.5, 0.1, -0.5},
+		{GamepadAxisRightTrigger, 1, 0.1, 1},
+		{GamepadAxisLeftTrigger, -0.95, -1, -0.95},
+	} {
+		if got := applyDeadzone(tc.axis, tc.value, tc.deadzone); got != tc.want {
+			t.Errorf("applyDeadzone(%v, %v, %v) = %v, want %v", tc.axis, tc.value, tc.deadzone, got, tc.want)
+		}
+	}
+}
+
+func TestGamepadStateConnect(t *testing.T) {
+	var gs gamepadState
+	gs.axes[GamepadAxisLeftX] = 0.5
+	gs.connect()
+
+	// a gamepad at rest does not differ from the state it connects with
+	for axis := range gs.axes {
+		value := float32(0)
+		if GamepadAxis(axis) == GamepadAxisLeftTrigger || GamepadAxis(axis) == GamepadAxisRightTrigger {
+			value = -1
+		}
+		if !gs.connected || gs.axes[axis] != value || gs.state.Axes[axis] != value {
+			t.Fatal(axis, gs.axes[axis], gs.state.Axes[axis])
+		}
+	}
+}
@@ -1,8 +1,9 @@
 package pancake
 
 import (
-	"context"
 	"image"
+	"sync"
+	"time"
 
 	gl "github.com/askeladdk/pancake/opengl"
 	"github.com/go-gl/glfw/v3.3/glfw"
@@ -11,27 +12,63 @@ import (
 
 type glfwWindow struct {
 	*glfw.Window
-	inputEvents     []interface{}
-	cursorEntered   bool
-	windowScale     int
-	resolutionScale int
-	viewport        image.Rectangle
-	resolution      image.Point
-	mousePosition   image.Point
+	inputEvents      []interface{}
+	cursorEntered    bool
+	mu               sync.Mutex
+	windowScale      int
+	scalingMode      ScalingMode
+	viewport         image.Rectangle
+	resolution       image.Point
+	bounds           image.Point
+	mousePosition    image.Point
+	monitor          *glfw.Monitor
+	windowedRect     image.Rectangle
+	upscaler         *upscaler
+	gamepads         [GamepadCount]gamepadState
+	gamepadDeadzone  float32
+	refreshDeltaTime float64
+	frameTimer       frameTimer
+	pacer            *framePacer
 }
 
 func newGlfwWindow(opt Options) (*glfwWindow, error) {
 	var wnd glfwWindow
 	var err error
 
+	// select the monitor used for fullscreen mode
+	wnd.monitor = glfw.GetPrimaryMonitor()
+	if monitors := glfw.GetMonitors(); opt.Monitor > 0 && opt.Monitor < len(monitors) {
+		wnd.monitor = monitors[opt.Monitor]
+	}
+
 	// create the window
-	glfw.WindowHint(glfw.Resizable, glfw.False)
+	glfw.WindowHint(glfw.Resizable, glfwBool(opt.Resizable))
 	glfw.WindowHint(glfw.ContextVersionMajor, 3)
 	glfw.WindowHint(glfw.ContextVersionMinor, 3)
 	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
 	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
 	glfw.WindowHint(glfw.DoubleBuffer, glfw.True)
-	if wnd.Window, err = glfw.CreateWindow(opt.WindowSize.X, opt.WindowSize.Y, opt.Title, nil, nil); err != nil {
+	glfw.WindowHint(glfw.ContextCreationAPI, opt.ContextAPI.hint())
+	glfw.WindowHint(glfw.OpenGLDebugContext, glfwBool(opt.Debug))
+	if opt.Upscale == UpscaleNone {
+		glfw.WindowHint(glfw.Samples, opt.Samples)
+	}
+	if opt.Headless {
+		glfw.WindowHint(glfw.Visible, glfw.False)
+		glfw.WindowHint(glfw.Focused, glfw.False)
+	}
+	wnd.windowedRect = image.Rectangle{Max: opt.WindowSize}
+	if opt.Fullscreen {
+		mode := wnd.monitor.GetVideoMode()
+		// leaving fullscreen restores the window centred on the monitor
+		x, y := wnd.monitor.GetPos()
+		wnd.windowedRect = centerRect(image.Rect(x, y, x+mode.Width, y+mode.Height), opt.WindowSize)
+		glfw.WindowHint(glfw.RefreshRate, mode.RefreshRate)
+		wnd.Window, err = glfw.CreateWindow(mode.Width, mode.Height, opt.Title, wnd.monitor, nil)
+	} else {
+		wnd.Window, err = glfw.CreateWindow(opt.WindowSize.X, opt.WindowSize.Y, opt.Title, nil, nil)
+	}
+	if err != nil {
 		return nil, err
 	}
 
@@ -41,63 +78,172 @@ func newGlfwWindow(opt Options) (*glfwWindow, error) {
 	wnd.SetCursorEnterCallback(wnd.cursorEnterCallback)
 	wnd.SetKeyCallback(wnd.keyCallback)
 	wnd.SetMouseButtonCallback(wnd.mouseCallback)
+	wnd.SetFramebufferSizeCallback(wnd.framebufferSizeCallback)
+	wnd.SetScrollCallback(wnd.scrollCallback)
+	wnd.SetFocusCallback(wnd.focusCallback)
+	wnd.SetIconifyCallback(wnd.iconifyCallback)
 
 	// set the window parameters
-	w, h := wnd.GetFramebufferSize()
 	wnd.resolution = opt.Resolution
-	wnd.windowScale = w / opt.WindowSize.X
-	wnd.viewport = logicalViewport(image.Pt(w, h), opt.Resolution)
-	wnd.resolutionScale = wnd.viewport.Size().X / wnd.resolution.X
+	wnd.gamepadDeadzone = float32(opt.GamepadDeadzone)
+	wnd.scalingMode = opt.ScalingMode
+	if opt.Upscale != UpscaleNone {
+		wnd.upscaler = &upscaler{filter: opt.Upscale, samples: opt.Samples}
+	}
+	wnd.resize(wnd.GetFramebufferSize())
+
+	// the refresh period is the timestep in LoopVSync mode
+	wnd.refreshDeltaTime = 1. / 60
+	if mode := wnd.monitor.GetVideoMode(); mode != nil && mode.RefreshRate > 0 {
+		wnd.refreshDeltaTime = 1 / float64(mode.RefreshRate)
+	}
+	wnd.pacer = newFramePacer(time.Duration(wnd.refreshDeltaTime*float64(time.Second)), opt.VSync)
 
 	wnd.MakeContextCurrent()
+	if opt.VSync {
+		glfw.SwapInterval(1)
+	} else {
+		glfw.SwapInterval(0)
+	}
 	return &wnd, nil
 }
 
+// resize recomputes the viewport parameters after the framebuffer has been resized.
+// Must be called from the main thread.
+func (wnd *glfwWindow) resize(width, height int) bool {
+	// the framebuffer is empty when the window is minimized
+	if width <= 0 || height <= 0 {
+		return false
+	}
+
+	w, _ := wnd.GetSize()
+
+	wnd.mu.Lock()
+	defer wnd.mu.Unlock()
+	if w > 0 {
+		wnd.windowScale = width / w
+	}
+	wnd.viewport, wnd.bounds = logicalViewport(image.Pt(width, height), wnd.resolution, wnd.scalingMode)
+	return true
+}
+
 func (wnd *glfwWindow) Begin() {
-	gl.Viewport(wnd.viewport)
+	wnd.frameTimer.beginFrame()
+
+	wnd.mu.Lock()
+	viewport, bounds := wnd.viewport, wnd.bounds
+	wnd.mu.Unlock()
+
+	if wnd.upscaler != nil {
+		wnd.upscaler.begin(bounds)
+	} else {
+		gl.Viewport(viewport)
+	}
 }
 
 func (wnd *glfwWindow) End() {
+	if wnd.upscaler != nil {
+		wnd.mu.Lock()
+		viewport := wnd.viewport
+		wnd.mu.Unlock()
+		wnd.upscaler.end(viewport)
+	}
+
+	wnd.frameTimer.endFrame()
 	mainthread.Call(wnd.SwapBuffers)
+	wnd.pacer.swapped(time.Now())
+	flushCalls()
+}
+
+func (wnd *glfwWindow) SetUpscaleShader(shader *ShaderProgram) {
+	if wnd.upscaler != nil {
+		wnd.upscaler.setShader(shader)
+	}
 }
 
 func (wnd *glfwWindow) Scissor(r image.Rectangle) Scissor {
-	r.Min = r.Min.Mul(wnd.resolutionScale)
-	r.Max = r.Max.Mul(wnd.resolutionScale)
-	r = r.Add(wnd.viewport.Min)
+	// the internal framebuffer is already in logical coordinates
+	if wnd.upscaler != nil {
+		return Scissor(r)
+	}
+
+	wnd.mu.Lock()
+	defer wnd.mu.Unlock()
+	r.Min = logicalToViewport(r.Min, wnd.viewport, wnd.bounds)
+	r.Max = logicalToViewport(r.Max, wnd.viewport, wnd.bounds)
 	return Scissor(r)
 }
 
+func (wnd *glfwWindow) Fullscreen() bool {
+	var fullscreen bool
+	mainthread.Call(func() {
+		fullscreen = wnd.GetMonitor() != nil
+	})
+	return fullscreen
+}
+
+func (wnd *glfwWindow) SetFullscreen(fullscreen bool) {
+	mainthread.Call(func() {
+		if fullscreen == (wnd.GetMonitor() != nil) {
+			return
+		} else if fullscreen {
+			x, y := wnd.GetPos()
+			w, h := wnd.GetSize()
+			wnd.windowedRect = image.Rect(x, y, x+w, y+h)
+			mode := wnd.monitor.GetVideoMode()
+			wnd.SetMonitor(wnd.monitor, 0, 0, mode.Width, mode.Height, mode.RefreshRate)
+		} else {
+			r := wnd.windowedRect
+			wnd.SetMonitor(nil, r.Min.X, r.Min.Y, r.Dx(), r.Dy(), 0)
+		}
+	})
+}
+
+// centerRect returns a rectangle of the given size centred in bounds.
+func centerRect(bounds image.Rectangle, size image.Point) image.Rectangle {
+	min := bounds.Min.Add(bounds.Size().Sub(size).Div(2))
+	return image.Rectangle{Min: min, Max: min.Add(size)}
+}
+
 func (wnd *glfwWindow) SetTitle(title string) {
 	mainthread.Call(func() {
 		wnd.Window.SetTitle(title)
 	})
 }
 
+func (wnd *glfwWindow) Screenshot() *image.RGBA {
+	if wnd.upscaler != nil && wnd.upscaler.canvas != nil {
+		return wnd.upscaler.canvas.Image()
+	}
+
+	wnd.mu.Lock()
+	viewport := wnd.viewport
+	wnd.mu.Unlock()
+	return readPixels(viewport)
+}
+
 func (wnd *glfwWindow) Bounds() image.Rectangle {
-	return image.Rectangle{Max: wnd.resolution}
+	wnd.mu.Lock()
+	defer wnd.mu.Unlock()
+	return image.Rectangle{Max: wnd.bounds}
 }
 
-func (wnd *glfwWindow) InputEvents(ctx context.Context, ch chan<- interface{}) bool {
+// pollEvents polls the window for input events.
+// The returned slice is only valid until the next call.
+func (wnd *glfwWindow) pollEvents() []interface{} {
+	wnd.inputEvents = wnd.inputEvents[:0]
+
 	if wnd.ShouldClose() {
 		wnd.SetShouldClose(false)
-		select {
-		case <-ctx.Done():
-			return false
-		case ch <- CloseEvent{}:
-		}
+		wnd.inputEvents = append(wnd.inputEvents, CloseEvent{})
 	}
 
-	mainthread.Call(glfw.PollEvents)
-	for _, inputEvent := range wnd.inputEvents {
-		select {
-		case <-ctx.Done():
-			return false
-		case ch <- inputEvent:
-		}
-	}
-	wnd.inputEvents = wnd.inputEvents[:0]
-	return true
+	mainthread.Call(func() {
+		glfw.PollEvents()
+		wnd.pollGamepads()
+	})
+
+	return wnd.inputEvents
 }
 
 func (wnd *glfwWindow) charCallback(_ *glfw.Window, char rune) {
@@ -116,19 +262,55 @@ func (wnd *glfwWindow) keyCallback(_ *glfw.Window, key glfw.Key, scancode int, a
 
 func (wnd *glfwWindow) cursorEnterCallback(_ *glfw.Window, entered bool) {
 	wnd.cursorEntered = entered
+	wnd.inputEvents = append(wnd.inputEvents, CursorEnterEvent{
+		Entered: entered,
+	})
+}
+
+func (wnd *glfwWindow) scrollCallback(_ *glfw.Window, x, y float64) {
+	if wnd.cursorEntered {
+		wnd.inputEvents = append(wnd.inputEvents, ScrollEvent{
+			X:        x,
+			Y:        y,
+			Position: wnd.mousePosition,
+		})
+	}
+}
+
+func (wnd *glfwWindow) focusCallback(_ *glfw.Window, focused bool) {
+	wnd.inputEvents = append(wnd.inputEvents, FocusEvent{
+		Focused: focused,
+	})
+}
+
+func (wnd *glfwWindow) iconifyCallback(_ *glfw.Window, iconified bool) {
+	wnd.inputEvents = append(wnd.inputEvents, IconifyEvent{
+		Iconified: iconified,
+	})
+}
+
+func (wnd *glfwWindow) framebufferSizeCallback(_ *glfw.Window, width, height int) {
+	if wnd.resize(width, height) {
+		wnd.mu.Lock()
+		viewport, bounds := wnd.viewport, wnd.bounds
+		wnd.mu.Unlock()
+		wnd.inputEvents = append(wnd.inputEvents, ResizeEvent{
+			Size:     image.Pt(width, height),
+			Viewport: viewport,
+			Bounds:   image.Rectangle{Max: bounds},
+		})
+	}
 }
 
 func (wnd *glfwWindow) cursorCallback(_ *glfw.Window, x, y float64) {
+	wnd.mu.Lock()
+	defer wnd.mu.Unlock()
+
 	mouse := image.Point{int(x), int(y)}.Mul(wnd.windowScale)
 
 	if wnd.cursorEntered && mouse.In(wnd.viewport) {
 		// Scale the mouse position from window to resolution coordinates.
-		mouse = mouse.Sub(wnd.viewport.Min)
-		vpsz := wnd.viewport.Size()
-		wnd.mousePosition = image.Point{
-			mouse.X * wnd.resolution.X / vpsz.X,
-			mouse.Y * wnd.resolution.Y / vpsz.Y,
-		}
+		wnd.mousePosition = viewportToLogical(mouse, wnd.viewport, wnd.bounds)
 
 		wnd.inputEvents = append(wnd.inputEvents, MouseMoveEvent{
 			Position: wnd.mousePosition,
@@ -146,6 +328,24 @@ func (wnd *glfwWindow) mouseCallback(_ *glfw.Window, button glfw.MouseButton, ac
 	}
 }
 
+func glfwBool(b bool) int {
+	if b {
+		return glfw.True
+	}
+	return glfw.False
+}
+
+func (api ContextAPI) hint() int {
+	switch api {
+	case ContextAPIEGL:
+		return glfw.EGLContextAPI
+	case ContextAPIOSMesa:
+		return glfw.OSMesaContextAPI
+	default:
+		return glfw.NativeContextAPI
+	}
+}
+
 func glfwModifiers(action glfw.Action, mod glfw.ModifierKey) (modifiers Modifiers) {
 	if action == glfw.Press {
 		modifiers |= ModPressed
@@ -180,10 +380,17 @@ func glfwInit(opt Options) (*glfwWindow, error) {
 	mainthread.Call(func() {
 		if err = glfw.Init(); err != nil {
 			return
+		} else if opt.GamepadMappings != "" && !glfw.UpdateGamepadMappings(opt.GamepadMappings) {
+			err = errInvalidGamepadMappings
+			return
 		} else if wnd, err = newGlfwWindow(opt); err != nil {
 			return
 		}
 		err = gl.Init(nil)
 	})
+
+	if err == nil && opt.Debug {
+		enableDebugOutput(opt.DebugLogger)
+	}
 	return wnd, err
 }
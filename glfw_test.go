@@ -0,0 +1,22 @@
+package pancake
+
+import (
+	"image"
+	"testing"
+)
+
+func TestCenterRect(t *testing.T) {
+	for _, tc := range []struct {
+		bounds image.Rectangle
+		size   image.Point
+		want   image.Rectangle
+	}{
+		{image.Rect(0, 0, 1920, 1080), image.Pt(640, 480), image.Rect(640, 300, 1280, 780)},
+		{image.Rect(1920, 0, 3200, 1024), image.Pt(320, 200), image.Rect(2400, 412, 2720, 612)},
+		{image.Rect(0, 0, 100, 100), image.Pt(200, 50), image.Rect(-50, 25, 150, 75)},
+	} {
+		if got := centerRect(tc.bounds, tc.size); got != tc.want {
+			t.Errorf("centerRect(%v, %v) = %v, want %v", tc.bounds, tc.size, got, tc.want)
+		}
+	}
+}
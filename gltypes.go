@@ -12,6 +12,15 @@ type TextureFilter uint32
 const (
 	FilterLinear TextureFilter = iota
 	FilterNearest
+	// FilterNearestMipmapNearest samples the nearest texel of the nearest mipmap.
+	// The mipmap filters can only be used as minifying filters.
+	FilterNearestMipmapNearest
+	// FilterLinearMipmapNearest interpolates texels of the nearest mipmap.
+	FilterLinearMipmapNearest
+	// FilterNearestMipmapLinear interpolates between the nearest texels of the two nearest mipmaps.
+	FilterNearestMipmapLinear
+	// FilterLinearMipmapLinear interpolates texels and mipmaps (trilinear filtering).
+	FilterLinearMipmapLinear
 )
 
 func (filter TextureFilter) param() gl.Enum {
@@ -20,27 +29,107 @@ func (filter TextureFilter) param() gl.Enum {
 		return gl.LINEAR
 	case FilterNearest:
 		return gl.NEAREST
+	case FilterNearestMipmapNearest:
+		return gl.NEAREST_MIPMAP_NEAREST
+	case FilterLinearMipmapNearest:
+		return gl.LINEAR_MIPMAP_NEAREST
+	case FilterNearestMipmapLinear:
+		return gl.NEAREST_MIPMAP_LINEAR
+	case FilterLinearMipmapLinear:
+		return gl.LINEAR_MIPMAP_LINEAR
 	default:
 		panic(errors.New("invalid filter"))
 	}
 }
 
+// mipmap reports whether the filter samples mipmaps.
+func (filter TextureFilter) mipmap() bool {
+	return filter >= FilterNearestMipmapNearest
+}
+
+// TextureWrap determines how texture coordinates outside [0, 1] are sampled.
+type TextureWrap uint32
+
+const (
+	// WrapRepeat repeats the texture.
+	WrapRepeat TextureWrap = iota
+	// WrapClampToEdge clamps to the texels at the edge.
+	WrapClampToEdge
+	// WrapMirroredRepeat repeats the texture, mirroring every other repetition.
+	WrapMirroredRepeat
+	// WrapClampToBorder samples the border color.
+	WrapClampToBorder
+)
+
+func (wrap TextureWrap) param() gl.Enum {
+	switch wrap {
+	case WrapRepeat:
+		return gl.REPEAT
+	case WrapClampToEdge:
+		return gl.CLAMP_TO_EDGE
+	case WrapMirroredRepeat:
+		return gl.MIRRORED_REPEAT
+	case WrapClampToBorder:
+		return gl.CLAMP_TO_BORDER
+	default:
+		panic(errors.New("invalid wrap mode"))
+	}
+}
+
 type ColorFormat uint32
 
 const (
 	ColorFormatRGBA ColorFormat = iota
 	ColorFormatRGB
 	ColorFormatIndexed
+	// ColorFormatRGBA16F stores half-float RGBA for HDR rendering.
+	// Pixels are transferred as float32.
+	ColorFormatRGBA16F
+	// ColorFormatR32F stores a single float32 channel.
+	ColorFormatR32F
+	// ColorFormatDepth stores 24-bit depth values.
+	// Pixels are transferred as uint32.
+	ColorFormatDepth
+	// ColorFormatDepthStencil stores 24-bit depth and 8-bit stencil values
+	// packed into a uint32.
+	ColorFormatDepthStencil
+	// ColorFormatR8 stores a single 8-bit channel, such as grayscale or alpha.
+	// It is sampled from the red channel.
+	ColorFormatR8
+	// ColorFormatRG8 stores two 8-bit channels.
+	ColorFormatRG8
+	// ColorFormatR16 stores a single 16-bit channel.
+	// Pixels are transferred as native endian uint16.
+	ColorFormatR16
+	// ColorFormatRGBA16 stores four 16-bit channels.
+	// Pixels are transferred as native endian uint16.
+	ColorFormatRGBA16
+	// ColorFormatR16F stores a single half-float channel.
+	// Pixels are transferred as float32.
+	ColorFormatR16F
+	// ColorFormatRG16F stores two half-float channels.
+	// Pixels are transferred as float32.
+	ColorFormatRG16F
+	// ColorFormatRG32F stores two float32 channels.
+	ColorFormatRG32F
+	// ColorFormatRGBA32F stores four float32 channels.
+	ColorFormatRGBA32F
 )
 
 func (format ColorFormat) format() gl.Enum {
 	switch format {
-	case ColorFormatRGBA:
+	case ColorFormatRGBA, ColorFormatRGBA16F, ColorFormatRGBA16, ColorFormatRGBA32F:
 		return gl.RGBA
 	case ColorFormatRGB:
 		return gl.RGB
-	case ColorFormatIndexed:
+	case ColorFormatIndexed, ColorFormatR8, ColorFormatR16, ColorFormatR16F, ColorFormatR32F:
 		return gl.RED
+	case ColorFormatRG8, ColorFormatRG16F, ColorFormatRG32F:
+		return gl.RG
+	case ColorFormatDepth:
+		return gl.DEPTH_COMPONENT
+	case ColorFormatDepthStencil:
+		return gl.DEPTH_STENCIL
 	default:
 		panic(errors.New("invalid color mode"))
 	}
@@ -52,26 +141,97 @@ func (format ColorFormat) internalFormat() gl.Enum {
 		return gl.RGBA
 	case ColorFormatRGB:
 		return gl.RGB
-	case ColorFormatIndexed:
+	case ColorFormatIndexed, ColorFormatR8:
 		return gl.R8
+	case ColorFormatRG8:
+		return gl.RG8
+	case ColorFormatR16:
+		return gl.R16
+	case ColorFormatRGBA16:
+		return gl.RGBA16
+	case ColorFormatR16F:
+		return gl.R16F
+	case ColorFormatRG16F:
+		return gl.RG16F
+	case ColorFormatRGBA16F:
+		return gl.RGBA16F
+	case ColorFormatR32F:
+		return gl.R32F
+	case ColorFormatRG32F:
+		return gl.RG32F
+	case ColorFormatRGBA32F:
+		return gl.RGBA32F
+	case ColorFormatDepth:
+		return gl.DEPTH_COMPONENT24
+	case ColorFormatDepthStencil:
+		return gl.DEPTH24_STENCIL8
 	default:
 		panic(errors.New("invalid color mode"))
 	}
 }
 
-func (format ColorFormat) pixelSize() int {
+// channels is the number of components per pixel that are transferred.
+func (format ColorFormat) channels() int {
 	switch format {
-	case ColorFormatRGBA:
+	case ColorFormatRGBA, ColorFormatRGBA16, ColorFormatRGBA16F, ColorFormatRGBA32F:
 		return 4
 	case ColorFormatRGB:
-		return 4
-	case ColorFormatIndexed:
+		return 3
+	case ColorFormatRG8, ColorFormatRG16F, ColorFormatRG32F:
+		return 2
+	case ColorFormatIndexed, ColorFormatR8, ColorFormatR16, ColorFormatR16F, ColorFormatR32F,
+		ColorFormatDepth, ColorFormatDepthStencil:
 		return 1
 	default:
 		panic(errors.New("invalid color mode"))
 	}
 }
 
+func (format ColorFormat) pixelSize() int {
+	switch format.xtype() {
+	case gl.UNSIGNED_SHORT:
+		return 2 * format.channels()
+	case gl.FLOAT, gl.UNSIGNED_INT, gl.UNSIGNED_INT_24_8:
+		return 4 * format.channels()
+	default:
+		return format.channels()
+	}
+}
+
+// xtype is the type of the pixel data that is transferred to and from the GL.
+func (format ColorFormat) xtype() gl.Enum {
+	switch format {
+	case ColorFormatR16, ColorFormatRGBA16:
+		return gl.UNSIGNED_SHORT
+	case ColorFormatR16F, ColorFormatRG16F, ColorFormatRGBA16F,
+		ColorFormatR32F, ColorFormatRG32F, ColorFormatRGBA32F:
+		return gl.FLOAT
+	case ColorFormatDepth:
+		return gl.UNSIGNED_INT
+	case ColorFormatDepthStencil:
+		return gl.UNSIGNED_INT_24_8
+	default:
+		return gl.UNSIGNED_BYTE
+	}
+}
+
+// depth reports whether the format stores depth values.
+func (format ColorFormat) depth() bool {
+	return format == ColorFormatDepth || format == ColorFormatDepthStencil
+}
+
+// rowAlignment returns the largest alignment of GL_UNPACK_ALIGNMENT and GL_PACK_ALIGNMENT
+// that is compatible with tightly packed rows of the given width.
+func (format ColorFormat) rowAlignment(width int) int {
+	rowSize := width * format.pixelSize()
+	for _, align := range []int{8, 4, 2} {
+		if rowSize%align == 0 {
+			return align
+		}
+	}
+	return 1
+}
+
 type Attrib uint32
 
 const (
@@ -0,0 +1,126 @@
+package pancake
+
+import (
+	"image"
+)
+
+// keySet is a bitset of keys.
+type keySet [KeyLast/64 + 1]uint64
+
+func (ks *keySet) set(k Key, v bool) {
+	if k < 0 || k > KeyLast {
+		return
+	} else if v {
+		ks[k/64] |= 1 << (k % 64)
+	} else {
+		ks[k/64] &^= 1 << (k % 64)
+	}
+}
+
+func (ks keySet) has(k Key) bool {
+	return k >= 0 && k <= KeyLast && ks[k/64]&(1<<(k%64)) != 0
+}
+
+// mouseSet is a bitset of mouse buttons.
+type mouseSet uint8
+
+func (ms *mouseSet) set(b MouseButton, v bool) {
+	if b < MouseButton0 || b > MouseButton7 {
+		return
+	} else if v {
+		*ms |= 1 << b
+	} else {
+		*ms &^= 1 << b
+	}
+}
+
+func (ms mouseSet) has(b MouseButton) bool {
+	return b >= MouseButton0 && b <= MouseButton7 && ms&(1<<b) != 0
+}
+
+// InputState is a snapshot of the keyboard and mouse state.
+// It is updated once per FrameEvent and reflects all input events
+// that were delivered before that FrameEvent.
+type InputState struct {
+	keysDown      keySet
+	keysPressed   keySet
+	keysReleased  keySet
+	mouseDown     mouseSet
+	mousePressed  mouseSet
+	mouseReleased mouseSet
+	mousePosition image.Point
+}
+
+// KeyDown reports whether the key is being held down.
+func (s InputState) KeyDown(k Key) bool {
+	return s.keysDown.has(k)
+}
+
+// KeyPressed reports whether the key was pressed since the previous frame.
+func (s InputState) KeyPressed(k Key) bool {
+	return s.keysPressed.has(k)
+}
+
+// KeyReleased reports whether the key was released since the previous frame.
+func (s InputState) KeyReleased(k Key) bool {
+	return s.keysReleased.has(k)
+}
+
+// MouseDown reports whether the mouse button is being held down.
+func (s InputState) MouseDown(b MouseButton) bool {
+	return s.mouseDown.has(b)
+}
+
+// MousePressed reports whether the mouse button was pressed since the previous frame.
+func (s InputState) MousePressed(b MouseButton) bool {
+	return s.mousePressed.has(b)
+}
+
+// MouseReleased reports whether the mouse button was released since the previous frame.
+func (s InputState) MouseReleased(b MouseButton) bool {
+	return s.mouseReleased.has(b)
+}
+
+// MousePosition reports the mouse position in logical coordinates.
+func (s InputState) MousePosition() image.Point {
+	return s.mousePosition
+}
+
+// update applies an input event to the state.
+func (s *InputState) update(event interface{}) {
+	switch e := event.(type) {
+	case KeyEvent:
+		if e.Pressed() {
+			s.keysDown.set(e.Key, true)
+			s.keysPressed.set(e.Key, true)
+		} else if e.Released() {
+			s.keysDown.set(e.Key, false)
+			s.keysReleased.set(e.Key, true)
+		}
+	case MouseEvent:
+		s.mousePosition = e.Position
+		if e.Pressed() {
+			s.mouseDown.set(e.Button, true)
+			s.mousePressed.set(e.Button, true)
+		} else if e.Released() {
+			s.mouseDown.set(e.Button, false)
+			s.mouseReleased.set(e.Button, true)
+		}
+	case MouseMoveEvent:
+		s.mousePosition = e.Position
+	case FocusEvent:
+		// keys released while unfocused are never reported
+		if !e.Focused {
+			s.keysDown = keySet{}
+			s.mouseDown = 0
+		}
+	}
+}
+
+// endFrame clears the per-frame state.
+func (s *InputState) endFrame() {
+	s.keysPressed = keySet{}
+	s.keysReleased = keySet{}
+	s.mousePressed = 0
+	s.mouseReleased = 0
+}
@@ -0,0 +1,45 @@
+package pancake
+
+import (
+	"image"
+	"testing"
+)
+
+func TestInputState(t *testing.T) {
+	var s InputState
+
+	s.update(KeyEvent{Key: KeyA, Modifiers: ModPressed})
+	s.update(MouseEvent{Button: MouseButton1, Modifiers: ModPressed, Position: image.Pt(3, 4)})
+	if !s.KeyDown(KeyA) || !s.KeyPressed(KeyA) || s.KeyReleased(KeyA) {
+		t.Fatal("key a")
+	} else if !s.MouseDown(MouseButton1) || !s.MousePressed(MouseButton1) {
+		t.Fatal("mouse 1")
+	} else if s.MousePosition() != image.Pt(3, 4) {
+		t.Fatal(s.MousePosition())
+	}
+
+	s.endFrame()
+	s.update(KeyEvent{Key: KeyA, Modifiers: ModRepeated})
+	if !s.KeyDown(KeyA) || s.KeyPressed(KeyA) {
+		t.Fatal("key a held")
+	}
+
+	s.endFrame()
+	s.update(KeyEvent{Key: KeyA, Modifiers: ModReleased})
+	s.update(MouseMoveEvent{Position: image.Pt(5, 6)})
+	if s.KeyDown(KeyA) || !s.KeyReleased(KeyA) {
+		t.Fatal("key a released")
+	} else if s.MousePosition() != image.Pt(5, 6) {
+		t.Fatal(s.MousePosition())
+	}
+
+	s.update(KeyEvent{Key: KeyUnknown, Modifiers: ModPressed})
+	if s.KeyDown(KeyUnknown) {
+		t.Fatal("unknown key")
+	}
+
+	s.update(FocusEvent{Focused: false})
+	if s.MouseDown(MouseButton1) {
+		t.Fatal("focus lost")
+	}
+}
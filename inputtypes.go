@@ -178,3 +178,44 @@ const (
 	KeyY            Key = 89
 	KeyZ            Key = 90
 )
+
+// Gamepad identifies a connected gamepad.
+type Gamepad int
+
+// GamepadCount is the maximum number of simultaneously connected gamepads.
+const GamepadCount = 16
+
+type GamepadButton int
+
+const (
+	GamepadButtonA           GamepadButton = 0
+	GamepadButtonB           GamepadButton = 1
+	GamepadButtonX           GamepadButton = 2
+	GamepadButtonY           GamepadButton = 3
+	GamepadButtonLeftBumper  GamepadButton = 4
+	GamepadButtonRightBumper GamepadButton = 5
+	GamepadButtonBack        GamepadButton = 6
+	GamepadButtonStart       GamepadButton = 7
+	GamepadButtonGuide       GamepadButton = 8
+	GamepadButtonLeftThumb   GamepadButton = 9
+	GamepadButtonRightThumb  GamepadButton = 10
+	GamepadButtonDpadUp      GamepadButton = 11
+	GamepadButtonDpadRight   GamepadButton = 12
+	GamepadButtonDpadDown    GamepadButton = 13
+	GamepadButtonDpadLeft    GamepadButton = 14
+	GamepadButtonCross       GamepadButton = GamepadButtonA
+	GamepadButtonCircle      GamepadButton = GamepadButtonB
+	GamepadButtonSquare      GamepadButton = GamepadButtonX
+	GamepadButtonTriangle    GamepadButton = GamepadButtonY
+)
+
+type GamepadAxis int
+
+const (
+	GamepadAxisLeftX        GamepadAxis = 0
+	GamepadAxisLeftY        GamepadAxis = 1
+	GamepadAxisRightX       GamepadAxis = 2
+	GamepadAxisRightY       GamepadAxis = 3
+	GamepadAxisLeftTrigger  GamepadAxis = 4
+	GamepadAxisRightTrigger GamepadAxis = 5
+)
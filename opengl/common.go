@@ -6,6 +6,7 @@ type (
 	Enum         uint32
 	Framebuffer  uint32
 	Program      uint32
+	Query        uint32
 	Renderbuffer uint32
 	Shader       uint32
 	Texture      uint32
@@ -0,0 +1,82 @@
+package opengl
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Error is a GL error code that was raised by a wrapped call.
+type Error struct {
+	// Op is the name of the call that raised the error.
+	// It is empty if the error was not attributed to a call.
+	Op string
+	// Code is the error code as reported by GetError.
+	Code Enum
+}
+
+func (err *Error) Error() string {
+	if err.Op == "" {
+		return ErrorString(err.Code)
+	}
+	return fmt.Sprintf("%s: %s", err.Op, ErrorString(err.Code))
+}
+
+// ErrorString returns the symbolic name of an error or framebuffer status code.
+func ErrorString(code Enum) string {
+	switch code {
+	case NO_ERROR:
+		return "GL_NO_ERROR"
+	case INVALID_ENUM:
+		return "GL_INVALID_ENUM"
+	case INVALID_VALUE:
+		return "GL_INVALID_VALUE"
+	case INVALID_OPERATION:
+		return "GL_INVALID_OPERATION"
+	case INVALID_FRAMEBUFFER_OPERATION:
+		return "GL_INVALID_FRAMEBUFFER_OPERATION"
+	case OUT_OF_MEMORY:
+		return "GL_OUT_OF_MEMORY"
+	case STACK_OVERFLOW:
+		return "GL_STACK_OVERFLOW"
+	case STACK_UNDERFLOW:
+		return "GL_STACK_UNDERFLOW"
+	case CONTEXT_LOST:
+		return "GL_CONTEXT_LOST"
+	case FRAMEBUFFER_UNDEFINED:
+		return "GL_FRAMEBUFFER_UNDEFINED"
+	case FRAMEBUFFER_INCOMPLETE_ATTACHMENT:
+		return "GL_FRAMEBUFFER_INCOMPLETE_ATTACHMENT"
+	case FRAMEBUFFER_INCOMPLETE_MISSING_ATTACHMENT:
+		return "GL_FRAMEBUFFER_INCOMPLETE_MISSING_ATTACHMENT"
+	case FRAMEBUFFER_INCOMPLETE_DRAW_BUFFER:
+		return "GL_FRAMEBUFFER_INCOMPLETE_DRAW_BUFFER"
+	case FRAMEBUFFER_INCOMPLETE_READ_BUFFER:
+		return "GL_FRAMEBUFFER_INCOMPLETE_READ_BUFFER"
+	case FRAMEBUFFER_INCOMPLETE_MULTISAMPLE:
+		return "GL_FRAMEBUFFER_INCOMPLETE_MULTISAMPLE"
+	case FRAMEBUFFER_INCOMPLETE_LAYER_TARGETS:
+		return "GL_FRAMEBUFFER_INCOMPLETE_LAYER_TARGETS"
+	case FRAMEBUFFER_UNSUPPORTED:
+		return "GL_FRAMEBUFFER_UNSUPPORTED"
+	default:
+		return fmt.Sprintf("GL error 0x%04X", uint32(code))
+	}
+}
+
+var errorChecking int32
+
+// SetErrorChecking enables or disables checking GetError after every wrapped call.
+// A call that raises an error panics with an *Error that names the call.
+// Error checking forces a round trip to the driver and should only be used for debugging.
+func SetErrorChecking(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&errorChecking, v)
+}
+
+// ErrorChecking reports whether error checking is enabled.
+func ErrorChecking() bool {
+	return atomic.LoadInt32(&errorChecking) != 0
+}
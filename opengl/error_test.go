@@ -0,0 +1,19 @@
+package opengl
+
+import "testing"
+
+func TestError(t *testing.T) {
+	for _, x := range []struct {
+		Err  *Error
+		Want string
+	}{
+		{&Error{Code: INVALID_ENUM}, "GL_INVALID_ENUM"},
+		{&Error{Op: "BindTexture", Code: INVALID_OPERATION}, "BindTexture: GL_INVALID_OPERATION"},
+		{&Error{Op: "CheckFramebufferStatus", Code: FRAMEBUFFER_UNSUPPORTED}, "CheckFramebufferStatus: GL_FRAMEBUFFER_UNSUPPORTED"},
+		{&Error{Code: 0x1234}, "GL error 0x1234"},
+	} {
+		if got := x.Err.Error(); got != x.Want {
+			t.Errorf("got %q, want %q", got, x.Want)
+		}
+	}
+}
@@ -17,41 +17,102 @@ func Init(param interface{}) error {
 	return gl.Init()
 }
 
-func BindBuffer(target Enum, buffer Buffer) {
+// call runs fn on the main thread and checks for errors if error checking is enabled.
+func call(name string, fn func()) {
+	checkGoroutine(name)
+
+	if !ErrorChecking() {
+		mainthread.Call(fn)
+		return
+	}
+
+	var code uint32
+	mainthread.Call(func() {
+		fn()
+		code = gl.GetError()
+	})
+
+	if code != gl.NO_ERROR {
+		panic(&Error{Op: name, Code: Enum(code)})
+	}
+}
+
+// ExtensionSupported reports whether the context supports the named extension.
+func ExtensionSupported(name string) bool {
+	var ok bool
+	mainthread.Call(func() {
+		var n int32
+		gl.GetIntegerv(gl.NUM_EXTENSIONS, &n)
+		for i := int32(0); i < n && !ok; i++ {
+			ok = gl.GoStr(gl.GetStringi(gl.EXTENSIONS, uint32(i))) == name
+		}
+	})
+	return ok
+}
+
+// DebugMessageCallback installs fn as the synchronous debug message callback
+// using KHR_debug or ARB_debug_output. It reports false if neither is supported.
+// The callback is invoked on the main thread and must not call into GL.
+func DebugMessageCallback(fn func(source, xtype Enum, id uint32, severity Enum, message string)) bool {
+	proc := func(source, xtype, id, severity uint32, length int32, message string, userParam unsafe.Pointer) {
+		fn(Enum(source), Enum(xtype), id, Enum(severity), message)
+	}
+
+	var ok bool
+	khr := ExtensionSupported("GL_KHR_debug")
+	arb := !khr && ExtensionSupported("GL_ARB_debug_output")
 	mainthread.Call(func() {
+		if ok = khr || arb; !ok {
+			return
+		}
+		if khr {
+			gl.Enable(gl.DEBUG_OUTPUT)
+			gl.DebugMessageCallback(proc, nil)
+		} else {
+			gl.DebugMessageCallbackARB(proc, nil)
+		}
+		gl.Enable(gl.DEBUG_OUTPUT_SYNCHRONOUS)
+	})
+	return ok
+}
+
+func BindBuffer(target Enum, buffer Buffer) {
+	call("BindBuffer", func() {
 		gl.BindBuffer(uint32(target), uint32(buffer))
 	})
 }
 
 func CreateBuffer() Buffer {
 	var buffer uint32
-	mainthread.Call(func() {
+	call("CreateBuffer", func() {
 		gl.GenBuffers(1, &buffer)
 	})
 	return Buffer(buffer)
 }
 
 func DeleteBuffer(buffer Buffer) {
+	checkGoroutine("DeleteBuffer")
 	mainthread.Go(func() {
 		gl.DeleteBuffers(1, (*uint32)(&buffer))
 	})
 }
 
 func BindFramebuffer(target Enum, frame Framebuffer) {
-	mainthread.Call(func() {
+	call("BindFramebuffer", func() {
 		gl.BindFramebuffer(uint32(target), uint32(frame))
 	})
 }
 
 func CreateFramebuffer() Framebuffer {
 	var frame uint32
-	mainthread.Call(func() {
+	call("CreateFramebuffer", func() {
 		gl.GenFramebuffers(1, &frame)
 	})
 	return Framebuffer(frame)
 }
 
 func DeleteFramebuffer(frame Framebuffer) {
+	checkGoroutine("DeleteFramebuffer")
 	mainthread.Go(func() {
 		gl.DeleteFramebuffers(1, (*uint32)(&frame))
 
@@ -59,142 +120,279 @@ func DeleteFramebuffer(frame Framebuffer) {
 }
 
 func BindRenderbuffer(rbuf Renderbuffer) {
-	mainthread.Call(func() {
+	call("BindRenderbuffer", func() {
 		gl.BindRenderbuffer(gl.RENDERBUFFER, uint32(rbuf))
 	})
 }
 
 func CreateRenderbuffer() Renderbuffer {
 	var rbuf uint32
-	mainthread.Call(func() {
+	call("CreateRenderbuffer", func() {
 		gl.GenRenderbuffers(1, &rbuf)
 	})
 	return Renderbuffer(rbuf)
 }
 
 func DeleteRenderbuffer(rbuf Renderbuffer) {
+	checkGoroutine("DeleteRenderbuffer")
 	mainthread.Go(func() {
-		gl.GenRenderbuffers(1, (*uint32)(&rbuf))
+		gl.DeleteRenderbuffers(1, (*uint32)(&rbuf))
 	})
 }
 
 func BindTexture(target Enum, texture Texture) {
-	mainthread.Call(func() {
+	call("BindTexture", func() {
 		gl.BindTexture(uint32(target), uint32(texture))
 	})
 }
 
 func CreateTexture() Texture {
 	var texture uint32
-	mainthread.Call(func() {
+	call("CreateTexture", func() {
 		gl.GenTextures(1, &texture)
 	})
 	return Texture(texture)
 }
 
 func DeleteTexture(texture Texture) {
+	checkGoroutine("DeleteTexture")
 	mainthread.Go(func() {
 		gl.DeleteTextures(1, (*uint32)(&texture))
 	})
 }
 
 func BindVertexArray(array VertexArray) {
-	mainthread.Call(func() {
+	call("BindVertexArray", func() {
 		gl.BindVertexArray(uint32(array))
 	})
 }
 
 func CreateVertexArray() VertexArray {
 	var array uint32
-	mainthread.Call(func() {
+	call("CreateVertexArray", func() {
 		gl.GenVertexArrays(1, &array)
 	})
 	return VertexArray(array)
 }
 
 func DeleteVertexArray(array VertexArray) {
+	checkGoroutine("DeleteVertexArray")
 	mainthread.Go(func() {
 		gl.DeleteVertexArrays(1, (*uint32)(&array))
 	})
 }
 
 func BindProgram(program Program) {
-	mainthread.Call(func() {
+	call("BindProgram", func() {
 		gl.UseProgram(uint32(program))
 	})
 }
 
 func CreateProgram() Program {
 	var program uint32
-	mainthread.Call(func() {
+	call("CreateProgram", func() {
 		program = gl.CreateProgram()
 	})
 	return Program(program)
 }
 
 func DeleteProgram(program Program) {
+	checkGoroutine("DeleteProgram")
 	mainthread.Go(func() {
 		gl.DeleteProgram(uint32(program))
 	})
 }
 
+func CreateQuery() Query {
+	var query uint32
+	call("CreateQuery", func() {
+		gl.GenQueries(1, &query)
+	})
+	return Query(query)
+}
+
+func DeleteQuery(query Query) {
+	checkGoroutine("DeleteQuery")
+	mainthread.Go(func() {
+		gl.DeleteQueries(1, (*uint32)(&query))
+	})
+}
+
+func BeginQuery(target Enum, query Query) {
+	call("BeginQuery", func() {
+		gl.BeginQuery(uint32(target), uint32(query))
+	})
+}
+
+func EndQuery(target Enum) {
+	call("EndQuery", func() {
+		gl.EndQuery(uint32(target))
+	})
+}
+
+func GetQueryObjecti(query Query, pname Enum) int {
+	var v int32
+	call("GetQueryObjecti", func() {
+		gl.GetQueryObjectiv(uint32(query), uint32(pname), &v)
+	})
+	return int(v)
+}
+
+func GetQueryObjectui64(query Query, pname Enum) uint64 {
+	var v uint64
+	call("GetQueryObjectui64", func() {
+		gl.GetQueryObjectui64v(uint32(query), uint32(pname), &v)
+	})
+	return v
+}
+
 func CreateShader(xtype Enum) Shader {
 	var shader uint32
-	mainthread.Call(func() {
+	call("CreateShader", func() {
 		shader = gl.CreateShader(uint32(xtype))
 	})
 	return Shader(shader)
 }
 
 func DeleteShader(shader Shader) {
+	checkGoroutine("DeleteShader")
 	mainthread.Go(func() {
 		gl.DeleteShader(uint32(shader))
 	})
 }
 
 func Enable(cap Enum) {
-	mainthread.Call(func() {
+	call("Enable", func() {
 		gl.Enable(uint32(cap))
 	})
 }
 
 func Disable(cap Enum) {
-	mainthread.Call(func() {
+	call("Disable", func() {
 		gl.Disable(uint32(cap))
 	})
 }
 
 func BlendFunc(sfactor, dfactor Enum) {
-	mainthread.Call(func() {
+	call("BlendFunc", func() {
 		gl.BlendFunc(uint32(sfactor), uint32(dfactor))
 	})
 }
 
+func BlendFuncSeparate(srcRGB, dstRGB, srcAlpha, dstAlpha Enum) {
+	call("BlendFuncSeparate", func() {
+		gl.BlendFuncSeparate(uint32(srcRGB), uint32(dstRGB), uint32(srcAlpha), uint32(dstAlpha))
+	})
+}
+
+func BlendEquationSeparate(modeRGB, modeAlpha Enum) {
+	call("BlendEquationSeparate", func() {
+		gl.BlendEquationSeparate(uint32(modeRGB), uint32(modeAlpha))
+	})
+}
+
+func DepthFunc(fn Enum) {
+	call("DepthFunc", func() {
+		gl.DepthFunc(uint32(fn))
+	})
+}
+
+func DepthMask(flag bool) {
+	call("DepthMask", func() {
+		gl.DepthMask(flag)
+	})
+}
+
+func StencilFunc(fn Enum, ref int, mask uint32) {
+	call("StencilFunc", func() {
+		gl.StencilFunc(uint32(fn), int32(ref), mask)
+	})
+}
+
+func StencilOp(sfail, dpfail, dppass Enum) {
+	call("StencilOp", func() {
+		gl.StencilOp(uint32(sfail), uint32(dpfail), uint32(dppass))
+	})
+}
+
+func StencilMask(mask uint32) {
+	call("StencilMask", func() {
+		gl.StencilMask(mask)
+	})
+}
+
+func ClearStencil(s int) {
+	call("ClearStencil", func() {
+		gl.ClearStencil(int32(s))
+	})
+}
+
+func ColorMask(r, g, b, a bool) {
+	call("ColorMask", func() {
+		gl.ColorMask(r, g, b, a)
+	})
+}
+
+func CullFace(mode Enum) {
+	call("CullFace", func() {
+		gl.CullFace(uint32(mode))
+	})
+}
+
+func FrontFace(mode Enum) {
+	call("FrontFace", func() {
+		gl.FrontFace(uint32(mode))
+	})
+}
+
 func GetInteger(name Enum) int {
 	var data int32
-	mainthread.Call(func() {
+	call("GetInteger", func() {
 		gl.GetIntegerv(uint32(name), &data)
 	})
 	return int(data)
 }
 
+func GetBooleanv(name Enum, data []bool) {
+	call("GetBooleanv", func() {
+		gl.GetBooleanv(uint32(name), &data[0])
+	})
+}
+
+func GetFloat(name Enum) float64 {
+	var data float32
+	call("GetFloat", func() {
+		gl.GetFloatv(uint32(name), &data)
+	})
+	return float64(data)
+}
+
 func GetString(name Enum) string {
 	var str string
-	mainthread.Call(func() {
+	call("GetString", func() {
 		str = gl.GoStr(gl.GetString(uint32(name)))
 	})
 	return str
 }
 
+// GetViewport returns the current viewport.
+func GetViewport() image.Rectangle {
+	var v [4]int32
+	call("GetViewport", func() {
+		gl.GetIntegerv(gl.VIEWPORT, &v[0])
+	})
+	return image.Rect(int(v[0]), int(v[1]), int(v[0]+v[2]), int(v[1]+v[3]))
+}
+
 func Viewport(r image.Rectangle) {
-	mainthread.Call(func() {
+	call("Viewport", func() {
 		size := r.Size()
 		gl.Viewport(int32(r.Min.X), int32(r.Min.Y), int32(size.X), int32(size.Y))
 	})
 }
 
 func Clear(mask Enum) {
-	mainthread.Call(func() {
+	call("Clear", func() {
 		gl.Clear(uint32(mask))
 	})
 }
@@ -208,7 +406,7 @@ func GetError() Enum {
 }
 
 func BlitNamedFramebuffer(src, dst Framebuffer, sr, dr image.Rectangle, mask, filter Enum) {
-	mainthread.Call(func() {
+	call("BlitNamedFramebuffer", func() {
 		gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, uint32(dst))
 		gl.BindFramebuffer(gl.READ_FRAMEBUFFER, uint32(src))
 		gl.BlitFramebuffer(
@@ -222,22 +420,38 @@ func BlitNamedFramebuffer(src, dst Framebuffer, sr, dr image.Rectangle, mask, fi
 }
 
 func FramebufferTexture2D(attachment, textarget Enum, texture Texture, level int) {
-	mainthread.Call(func() {
+	call("FramebufferTexture2D", func() {
 		gl.FramebufferTexture2D(gl.FRAMEBUFFER, uint32(attachment),
 			uint32(textarget), uint32(texture), int32(level))
 	})
 }
 
-func FramebufferRenderbuffer(attachment, rbuffer Renderbuffer) {
-	mainthread.Call(func() {
+func FramebufferRenderbuffer(attachment Enum, rbuffer Renderbuffer) {
+	call("FramebufferRenderbuffer", func() {
 		gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, uint32(attachment),
 			gl.RENDERBUFFER, uint32(rbuffer))
 	})
 }
 
+func DrawBuffers(bufs []Enum) {
+	call("DrawBuffers", func() {
+		if len(bufs) == 0 {
+			gl.DrawBuffer(gl.NONE)
+			return
+		}
+		gl.DrawBuffers(int32(len(bufs)), (*uint32)(&bufs[0]))
+	})
+}
+
+func ReadBuffer(mode Enum) {
+	call("ReadBuffer", func() {
+		gl.ReadBuffer(uint32(mode))
+	})
+}
+
 func CheckFramebufferStatus() Enum {
 	var status uint32
-	mainthread.Call(func() {
+	call("CheckFramebufferStatus", func() {
 		status = gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
 	})
 	return Enum(status)
@@ -245,64 +459,86 @@ func CheckFramebufferStatus() Enum {
 
 func GetUniformLocation(program Program, name string) Uniform {
 	var loc int32
-	mainthread.Call(func() {
+	call("GetUniformLocation", func() {
 		loc = gl.GetUniformLocation(uint32(program), gl.Str(name+"\x00"))
 	})
 	return Uniform(loc)
 }
 
+func GetUniformfv(program Program, location Uniform, params []float64) {
+	call("GetUniformfv", func() {
+		var vs [16]float32
+		gl.GetUniformfv(uint32(program), int32(location), &vs[0])
+		for i := range params {
+			params[i] = float64(vs[i])
+		}
+	})
+}
+
 func Uniform1i(dst Uniform, v0 int) {
-	mainthread.Call(func() {
+	call("Uniform1i", func() {
 		gl.Uniform1i(int32(dst), int32(v0))
 	})
 }
 
 func Uniform1ui(dst Uniform, v0 uint) {
-	mainthread.Call(func() {
+	call("Uniform1ui", func() {
 		gl.Uniform1ui(int32(dst), uint32(v0))
 	})
 }
 
 func Uniform1f(dst Uniform, v0 float64) {
-	mainthread.Call(func() {
+	call("Uniform1f", func() {
 		gl.Uniform1f(int32(dst), float32(v0))
 	})
 }
 
 func Uniform1fv(dst Uniform, v []float64) {
+	if len(v) == 0 {
+		return
+	}
 	vs32 := make([]float32, len(v))
 	for i, f64 := range v {
 		vs32[i] = float32(f64)
 	}
-	mainthread.Call(func() {
+	call("Uniform1fv", func() {
 		gl.Uniform1fv(int32(dst), int32(len(v)), &vs32[0])
 	})
 }
 
 func Uniform2fv(dst Uniform, vs []mathx.Vec2) {
+	if len(vs) == 0 {
+		return
+	}
 	vs32 := make([]float32, 2*len(vs))
 	for i, vi := range vs {
 		vs32[2*i+0] = float32(vi[0])
 		vs32[2*i+1] = float32(vi[1])
 	}
-	mainthread.Call(func() {
-		gl.Uniform2fv(int32(dst), int32(2*len(vs)), &vs32[0])
+	call("Uniform2fv", func() {
+		gl.Uniform2fv(int32(dst), int32(len(vs)), &vs32[0])
 	})
 }
 
 func Uniform3fv(dst Uniform, vs []mathx.Vec3) {
+	if len(vs) == 0 {
+		return
+	}
 	vs32 := make([]float32, 3*len(vs))
 	for i, vi := range vs {
 		vs32[3*i+0] = float32(vi[0])
 		vs32[3*i+1] = float32(vi[1])
 		vs32[3*i+2] = float32(vi[2])
 	}
-	mainthread.Call(func() {
-		gl.Uniform3fv(int32(dst), int32(3*len(vs)), &vs32[0])
+	call("Uniform3fv", func() {
+		gl.Uniform3fv(int32(dst), int32(len(vs)), &vs32[0])
 	})
 }
 
 func Uniform4fv(dst Uniform, vs []mathx.Vec4) {
+	if len(vs) == 0 {
+		return
+	}
 	vs32 := make([]float32, 4*len(vs))
 	for i, vi := range vs {
 		vs32[4*i+0] = float32(vi[0])
@@ -310,12 +546,15 @@ func Uniform4fv(dst Uniform, vs []mathx.Vec4) {
 		vs32[4*i+2] = float32(vi[2])
 		vs32[4*i+3] = float32(vi[3])
 	}
-	mainthread.Call(func() {
-		gl.Uniform4fv(int32(dst), int32(4*len(vs)), &vs32[0])
+	call("Uniform4fv", func() {
+		gl.Uniform4fv(int32(dst), int32(len(vs)), &vs32[0])
 	})
 }
 
 func UniformMatrix3fv(dst Uniform, vs []mathx.Mat3) {
+	if len(vs) == 0 {
+		return
+	}
 	vs32 := make([]float32, 9*len(vs))
 	for i, vi := range vs {
 		vs32[9*i+0] = float32(vi[0])
@@ -328,12 +567,15 @@ func UniformMatrix3fv(dst Uniform, vs []mathx.Mat3) {
 		vs32[9*i+7] = float32(vi[7])
 		vs32[9*i+8] = float32(vi[8])
 	}
-	mainthread.Call(func() {
+	call("UniformMatrix3fv", func() {
 		gl.UniformMatrix3fv(int32(dst), int32(len(vs)), false, &vs32[0])
 	})
 }
 
 func UniformMatrix4fv(dst Uniform, vs []mathx.Mat4) {
+	if len(vs) == 0 {
+		return
+	}
 	vs32 := make([]float32, 16*len(vs))
 	for i, vi := range vs {
 		vs32[16*i+0] = float32(vi[0])
@@ -353,13 +595,13 @@ func UniformMatrix4fv(dst Uniform, vs []mathx.Mat4) {
 		vs32[16*i+14] = float32(vi[14])
 		vs32[16*i+15] = float32(vi[15])
 	}
-	mainthread.Call(func() {
+	call("UniformMatrix4fv", func() {
 		gl.UniformMatrix4fv(int32(dst), int32(len(vs)), false, &vs32[0])
 	})
 }
 
 func ShaderSource(shader Shader, source string) {
-	mainthread.Call(func() {
+	call("ShaderSource", func() {
 		src, free := gl.Strs(source)
 		srclen := int32(len(source))
 		defer free()
@@ -368,14 +610,14 @@ func ShaderSource(shader Shader, source string) {
 }
 
 func CompileShader(shader Shader) {
-	mainthread.Call(func() {
+	call("CompileShader", func() {
 		gl.CompileShader(uint32(shader))
 	})
 }
 
 func GetShaderi(shader Shader, pname Enum) int {
 	var v int32
-	mainthread.Call(func() {
+	call("GetShaderi", func() {
 		gl.GetShaderiv(uint32(shader), uint32(pname), &v)
 	})
 	return int(v)
@@ -383,7 +625,7 @@ func GetShaderi(shader Shader, pname Enum) int {
 
 func GetShaderInfoLog(shader Shader) string {
 	var str string
-	mainthread.Call(func() {
+	call("GetShaderInfoLog", func() {
 		var logLen int32
 		gl.GetShaderiv(uint32(shader), gl.INFO_LOG_LENGTH, &logLen)
 		infoLog := make([]byte, logLen)
@@ -394,20 +636,20 @@ func GetShaderInfoLog(shader Shader) string {
 }
 
 func AttachShader(program Program, shader Shader) {
-	mainthread.Call(func() {
+	call("AttachShader", func() {
 		gl.AttachShader(uint32(program), uint32(shader))
 	})
 }
 
 func LinkProgram(program Program) {
-	mainthread.Call(func() {
+	call("LinkProgram", func() {
 		gl.LinkProgram(uint32(program))
 	})
 }
 
 func GetProgrami(program Program, pname Enum) int {
 	var v int32
-	mainthread.Call(func() {
+	call("GetProgrami", func() {
 		gl.GetProgramiv(uint32(program), uint32(pname), &v)
 	})
 	return int(v)
@@ -415,7 +657,7 @@ func GetProgrami(program Program, pname Enum) int {
 
 func GetProgramInfoLog(program Program) string {
 	var str string
-	mainthread.Call(func() {
+	call("GetProgramInfoLog", func() {
 		var logLen int32
 		gl.GetProgramiv(uint32(program), gl.INFO_LOG_LENGTH, &logLen)
 		infoLog := make([]byte, logLen)
@@ -426,25 +668,57 @@ func GetProgramInfoLog(program Program) string {
 }
 
 func ActiveTexture(target Enum) {
-	mainthread.Call(func() {
+	call("ActiveTexture", func() {
 		gl.ActiveTexture(uint32(target))
 	})
 }
 
 func GenerateMipmap(target Enum) {
-	mainthread.Call(func() {
+	call("GenerateMipmap", func() {
 		gl.GenerateMipmap(uint32(target))
 	})
 }
 
+func PixelStorei(pname Enum, param int) {
+	call("PixelStorei", func() {
+		gl.PixelStorei(uint32(pname), int32(param))
+	})
+}
+
 func TexParameteri(target, pname, param Enum) {
-	mainthread.Call(func() {
+	call("TexParameteri", func() {
 		gl.TexParameteri(uint32(target), uint32(pname), int32(param))
 	})
 }
 
+func TexParameteriv(target, pname Enum, params []int) {
+	call("TexParameteriv", func() {
+		var vs [4]int32
+		for i := range params {
+			vs[i] = int32(params[i])
+		}
+		gl.TexParameteriv(uint32(target), uint32(pname), &vs[0])
+	})
+}
+
+func TexParameterf(target, pname Enum, param float64) {
+	call("TexParameterf", func() {
+		gl.TexParameterf(uint32(target), uint32(pname), float32(param))
+	})
+}
+
+func TexParameterfv(target, pname Enum, params []float64) {
+	call("TexParameterfv", func() {
+		var vs [4]float32
+		for i := range params {
+			vs[i] = float32(params[i])
+		}
+		gl.TexParameterfv(uint32(target), uint32(pname), &vs[0])
+	})
+}
+
 func TexSubImage2D(target Enum, level int, x, y, width, height int, format, xtype Enum, data []byte) {
-	mainthread.Call(func() {
+	call("TexSubImage2D", func() {
 		gl.TexSubImage2D(uint32(target), int32(level),
 			int32(x), int32(y), int32(width), int32(height),
 			uint32(format), uint32(xtype), Ptr(data))
@@ -452,13 +726,21 @@ func TexSubImage2D(target Enum, level int, x, y, width, height int, format, xtyp
 }
 
 func GetTexImage(target Enum, level int, format, xtype Enum, data []byte) {
-	mainthread.Call(func() {
+	call("GetTexImage", func() {
 		gl.GetTexImage(uint32(target), int32(level), uint32(format), uint32(xtype), Ptr(data))
 	})
 }
 
+func ReadPixels(r image.Rectangle, format, xtype Enum, data []byte) {
+	call("ReadPixels", func() {
+		size := r.Size()
+		gl.ReadPixels(int32(r.Min.X), int32(r.Min.Y), int32(size.X), int32(size.Y),
+			uint32(format), uint32(xtype), Ptr(data))
+	})
+}
+
 func TexImage2D(target Enum, level int, internalFormat Enum, width, height int, format, xtype Enum, data []byte) {
-	mainthread.Call(func() {
+	call("TexImage2D", func() {
 		gl.TexImage2D(
 			uint32(target),
 			int32(level),
@@ -474,78 +756,78 @@ func TexImage2D(target Enum, level int, internalFormat Enum, width, height int,
 }
 
 func VertexAttribPointer(dst Attrib, size int, xtype Enum, normalized bool, stride, offset int) {
-	mainthread.Call(func() {
+	call("VertexAttribPointer", func() {
 		gl.VertexAttribPointer(uint32(dst), int32(size), uint32(xtype),
 			normalized, int32(stride), ptrOffset(offset))
 	})
 }
 
 func VertexAttribDivisor(dst Attrib, divisor int) {
-	mainthread.Call(func() {
+	call("VertexAttribDivisor", func() {
 		gl.VertexAttribDivisor(uint32(dst), uint32(divisor))
 	})
 }
 
 func EnableVertexAttribArray(dst Attrib) {
-	mainthread.Call(func() {
+	call("EnableVertexAttribArray", func() {
 		gl.EnableVertexAttribArray(uint32(dst))
 	})
 }
 
 func DrawArrays(mode Enum, first, count int) {
-	mainthread.Call(func() {
+	call("DrawArrays", func() {
 		gl.DrawArrays(uint32(mode), int32(first), int32(count))
 	})
 }
 
 func DrawArraysInstanced(mode Enum, first, count, instances int) {
-	mainthread.Call(func() {
+	call("DrawArraysInstanced", func() {
 		gl.DrawArraysInstanced(uint32(mode), int32(first), int32(count), int32(instances))
 	})
 }
 
 func DrawElements(mode Enum, count int, xtype Enum, indices int) {
-	mainthread.Call(func() {
+	call("DrawElements", func() {
 		gl.DrawElements(uint32(mode), int32(count), uint32(xtype), ptrOffset(indices))
 	})
 }
 
 func DrawElementsInstanced(mode Enum, count int, xtype Enum, indices, instanceCount int) {
-	mainthread.Call(func() {
+	call("DrawElementsInstanced", func() {
 		gl.DrawElementsInstanced(uint32(mode), int32(count), uint32(xtype), ptrOffset(indices), int32(instanceCount))
 	})
 }
 
 func BufferSubData(target Enum, offset, size int, data unsafe.Pointer) {
-	mainthread.Call(func() {
+	call("BufferSubData", func() {
 		gl.BufferSubData(uint32(target), offset, size, data)
 	})
 }
 
 func BufferData(target Enum, len int, data unsafe.Pointer, usage Enum) {
-	mainthread.Call(func() {
+	call("BufferData", func() {
 		gl.BufferData(uint32(target), len, data, uint32(usage))
 	})
 }
 
 func Flush() {
-	mainthread.Call(gl.Flush)
+	call("Flush", gl.Flush)
 }
 
 func ClearColor(r, g, b, a float64) {
-	mainthread.Call(func() {
+	call("ClearColor", func() {
 		gl.ClearColor(float32(r), float32(g), float32(b), float32(a))
 	})
 }
 
 func RenderbufferStorage(internalFormat Enum, width, height, samples int) {
-	mainthread.Call(func() {
+	call("RenderbufferStorage", func() {
 		gl.RenderbufferStorageMultisample(gl.RENDERBUFFER, int32(samples), uint32(internalFormat), int32(width), int32(height))
 	})
 }
 
 func Scissor(r image.Rectangle) {
-	mainthread.Call(func() {
+	call("Scissor", func() {
 		size := r.Size()
 		gl.Scissor(int32(r.Min.X), int32(r.Min.Y), int32(size.X), int32(size.Y))
 	})
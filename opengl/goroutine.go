@@ -0,0 +1,47 @@
+package opengl
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+)
+
+// owner is the id of the goroutine that is allowed to issue GL calls, or zero if unchecked.
+var owner int64
+
+// SetGoroutineChecking enables or disables checking that GL calls are issued
+// from a single goroutine. When enabled, the calling goroutine becomes the owner
+// of the GL context and a wrapped call from any other goroutine panics.
+// GL calls are always executed on the main thread regardless of this setting,
+// but the bind state that is cached on top of them is not safe for concurrent use.
+// Goroutine checking is slow and should only be used for debugging.
+func SetGoroutineChecking(enabled bool) {
+	var id int64
+	if enabled {
+		id = goroutineID()
+	}
+	atomic.StoreInt64(&owner, id)
+}
+
+// checkGoroutine panics if the calling goroutine does not own the GL context.
+func checkGoroutine(name string) {
+	if want := atomic.LoadInt64(&owner); want != 0 {
+		if id := goroutineID(); id != want {
+			panic(fmt.Errorf("opengl: %s called from goroutine %d but the GL context is owned by goroutine %d", name, id, want))
+		}
+	}
+}
+
+// goroutineID parses the id of the calling goroutine from its stack trace.
+func goroutineID() int64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseInt(string(b), 10, 64)
+	return id
+}
@@ -0,0 +1,20 @@
+package opengl
+
+import "testing"
+
+func TestCheckGoroutine(t *testing.T) {
+	SetGoroutineChecking(true)
+	defer SetGoroutineChecking(false)
+
+	checkGoroutine("Owner")
+
+	done := make(chan interface{})
+	go func() {
+		defer func() { done <- recover() }()
+		checkGoroutine("Other")
+	}()
+
+	if <-done == nil {
+		t.Fatal("expected a panic")
+	}
+}
@@ -0,0 +1,73 @@
+package pancake
+
+import (
+	"sync"
+	"time"
+)
+
+// vsyncIgnoredSwaps is the number of consecutive buffer swaps that must complete
+// in less than half the refresh period before vsync is considered ignored.
+const vsyncIgnoredSwaps = 10
+
+// framePacer decides how long the event loop idles between DrawEvents.
+// DrawEvents are paced at their own interval independently of the FrameEvents
+// of LoopFixed, so that there are DrawEvents in between FrameEvents to interpolate.
+// If vsync is enabled the buffer swaps pace the loop instead, unless
+// the driver does not wait for the vertical refresh.
+type framePacer struct {
+	mu sync.Mutex
+	// refresh is the refresh period of the monitor.
+	refresh time.Duration
+	vsync   bool
+	// next is the time at which the next DrawEvent is due.
+	next time.Time
+	// lastSwap is the time at which the last buffer swap completed.
+	lastSwap time.Time
+	// shortSwaps counts consecutive swaps that completed too soon for vsync.
+	shortSwaps int
+}
+
+func newFramePacer(refresh time.Duration, vsync bool) *framePacer {
+	return &framePacer{refresh: refresh, vsync: vsync}
+}
+
+// swapped records the time at which a buffer swap completed.
+func (p *framePacer) swapped(t time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.lastSwap.IsZero() && p.shortSwaps < vsyncIgnoredSwaps {
+		if t.Sub(p.lastSwap) < p.refresh/2 {
+			p.shortSwaps++
+		} else {
+			p.shortSwaps = 0
+		}
+	}
+	p.lastSwap = t
+}
+
+// vsyncIgnored reports whether vsync is enabled but the buffer swaps do not wait for it.
+func (p *framePacer) vsyncIgnored() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.vsync && p.shortSwaps >= vsyncIgnoredSwaps
+}
+
+// idle returns how long to wait at time now until the next DrawEvent
+// is due, given the target interval between DrawEvents.
+func (p *framePacer) idle(now time.Time, interval time.Duration) time.Duration {
+	if p.vsync && !p.vsyncIgnored() {
+		return 0
+	}
+
+	// schedule draws at a steady rate but do not catch up on missed draws
+	next := p.next.Add(interval)
+	if next.Before(now.Add(-interval)) {
+		next = now
+	}
+	p.next = next
+
+	if idle := next.Sub(now); idle > 0 {
+		return idle
+	}
+	return 0
+}
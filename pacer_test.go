@@ -0,0 +1,65 @@
+package pancake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFramePacerIdle(t *testing.T) {
+	const interval = 10 * time.Millisecond
+	p := newFramePacer(interval, false)
+	now := time.Unix(0, 0)
+
+	// the first draw is due immediately
+	if idle := p.idle(now, interval); idle != 0 {
+		t.Fatal(idle)
+	}
+
+	// a fast frame waits for the rest of the interval
+	now = now.Add(3 * time.Millisecond)
+	if idle := p.idle(now, interval); idle != 7*time.Millisecond {
+		t.Fatal(idle)
+	}
+
+	// a slow frame does not wait
+	now = now.Add(7*time.Millisecond + 12*time.Millisecond)
+	if idle := p.idle(now, interval); idle != 0 {
+		t.Fatal(idle)
+	}
+
+	// a long stall is not caught up on
+	now = now.Add(time.Second)
+	if idle := p.idle(now, interval); idle != 0 {
+		t.Fatal(idle)
+	} else if idle := p.idle(now, interval); idle != interval {
+		t.Fatal(idle)
+	}
+}
+
+func TestFramePacerVSync(t *testing.T) {
+	const refresh = 16 * time.Millisecond
+	p := newFramePacer(refresh, true)
+	now := time.Unix(0, 0)
+
+	// swaps that wait for the refresh pace the loop
+	for i := 0; i < 2*vsyncIgnoredSwaps; i++ {
+		now = now.Add(refresh)
+		p.swapped(now)
+		if idle := p.idle(now, refresh); idle != 0 {
+			t.Fatal(i, idle)
+		}
+	}
+
+	// swaps that return immediately fall back to sleeping
+	for i := 0; i < vsyncIgnoredSwaps; i++ {
+		now = now.Add(time.Millisecond)
+		p.swapped(now)
+	}
+	if !p.vsyncIgnored() {
+		t.Fatal("vsync ignored")
+	} else if idle := p.idle(now, refresh); idle != 0 {
+		t.Fatal(idle)
+	} else if idle := p.idle(now, refresh); idle != refresh {
+		t.Fatal(idle)
+	}
+}
@@ -0,0 +1,141 @@
+package pancake
+
+import (
+	"errors"
+	"image"
+	"image/color"
+)
+
+// PaletteSize is the number of colors in a Palette,
+// which is the number of indices that a ColorFormatIndexed Texture can address.
+const PaletteSize = 256
+
+// Palette is a Texture of PaletteSize colors that a shader looks up
+// the indices of a ColorFormatIndexed Texture in.
+// Indexed images can be recolored by binding a different Palette or by
+// changing the colors of a Palette at runtime.
+//
+//	tex := pancake.NewTextureFromImage(img, pancake.FilterNearest)
+//	palette := pancake.NewPalette(img.Palette)
+//	palette.BeginAt(1)
+//	... (draw tex with a shader that looks up the palette at texture unit 1)
+//	palette.EndAt(1)
+type Palette struct {
+	texture *Texture
+	pixels  []byte
+}
+
+// NewPalette creates a Palette from a color.Palette of at most PaletteSize colors.
+// The remaining colors are transparent black.
+func NewPalette(p color.Palette) *Palette {
+	if len(p) > PaletteSize {
+		panic(errors.New("too many colors in palette"))
+	}
+
+	pixels := make([]byte, 4*PaletteSize)
+	for i, c := range p {
+		setPaletteColor(pixels, i, c)
+	}
+
+	return &Palette{
+		texture: NewTextureWithOptions(image.Point{PaletteSize, 1}, ColorFormatRGBA, pixels, TextureOptions{
+			MinFilter: FilterNearest,
+			MagFilter: FilterNearest,
+			WrapS:     WrapClampToEdge,
+			WrapT:     WrapClampToEdge,
+		}),
+		pixels: pixels,
+	}
+}
+
+func setPaletteColor(pixels []byte, i int, c color.Color) {
+	rgba := color.RGBAModel.Convert(c).(color.RGBA)
+	copy(pixels[4*i:], []byte{rgba.R, rgba.G, rgba.B, rgba.A})
+}
+
+// Texture returns the texture of the Palette.
+// Its pixels must only be changed through the Palette.
+func (p *Palette) Texture() *Texture {
+	return p.texture
+}
+
+// BeginAt binds the Palette to a texture unit.
+func (p *Palette) BeginAt(unit int) {
+	p.texture.BeginAt(unit)
+}
+
+// EndAt unbinds the Palette from a texture unit.
+func (p *Palette) EndAt(unit int) {
+	p.texture.EndAt(unit)
+}
+
+// Delete deletes the texture of the Palette. It is safe to call Delete more than once.
+func (p *Palette) Delete() {
+	p.texture.Delete()
+}
+
+// Len returns PaletteSize.
+func (p *Palette) Len() int {
+	return PaletteSize
+}
+
+// At returns the color at an index.
+func (p *Palette) At(i int) color.Color {
+	return color.RGBA{p.pixels[4*i+0], p.pixels[4*i+1], p.pixels[4*i+2], p.pixels[4*i+3]}
+}
+
+// SetColor replaces the color at an index.
+func (p *Palette) SetColor(i int, c color.Color) {
+	if i < 0 || i >= PaletteSize {
+		panic(errors.New("palette index out of range"))
+	}
+
+	setPaletteColor(p.pixels, i, c)
+	p.update(i, i+1)
+}
+
+// SetColors replaces the colors starting at an index.
+func (p *Palette) SetColors(i int, colors color.Palette) {
+	if i < 0 || i >= PaletteSize {
+		panic(errors.New("palette index out of range"))
+	} else if i+len(colors) > PaletteSize {
+		panic(errors.New("too many colors in palette"))
+	}
+
+	for j, c := range colors {
+		setPaletteColor(p.pixels, i+j, c)
+	}
+	p.update(i, i+len(colors))
+}
+
+// Cycle rotates the colors in the range [lo, hi) by n positions towards
+// the end of the range, wrapping around to the start.
+// Calling Cycle every few frames animates palette-cycling effects
+// such as flowing water without redrawing the indexed images.
+func (p *Palette) Cycle(lo, hi, n int) {
+	if lo < 0 || hi > PaletteSize || lo >= hi {
+		panic(errors.New("invalid palette range"))
+	}
+
+	count := hi - lo
+	if n %= count; n < 0 {
+		n += count
+	}
+	if n == 0 {
+		return
+	}
+
+	colors := p.pixels[4*lo : 4*hi]
+	rotated := make([]byte, len(colors))
+	copy(rotated[4*n:], colors[:4*(count-n)])
+	copy(rotated, colors[4*(count-n):])
+	copy(colors, rotated)
+	p.update(lo, hi)
+}
+
+// update uploads the colors in the range [lo, hi).
+func (p *Palette) update(lo, hi int) {
+	if lo < hi {
+		p.texture.SetPixelsRegion(image.Rect(lo, 0, hi, 1), p.pixels[4*lo:4*hi])
+	}
+}
@@ -0,0 +1,43 @@
+package pancake
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+)
+
+func TestPalette(t *testing.T) {
+	palette := NewPalette(color.Palette{
+		color.RGBA{1, 0, 0, 255},
+		color.RGBA{2, 0, 0, 255},
+		color.RGBA{3, 0, 0, 255},
+	})
+	defer palette.Delete()
+
+	palette.Cycle(0, 3, 1)
+	palette.SetColor(3, color.RGBA{4, 0, 0, 255})
+
+	want := []byte{3, 1, 2, 4}
+	pixels := palette.Texture().Pixels(nil)
+	for i, r := range want {
+		if pixels[4*i] != r || palette.At(i).(color.RGBA).R != r {
+			t.Fatal(i, pixels[4*i])
+		}
+	}
+
+	if !bytes.Equal(pixels[4*len(want):], make([]byte, 4*(PaletteSize-len(want)))) {
+		t.Fatal("unused colors")
+	}
+}
+
+func TestPaletteSetColorOutOfRange(t *testing.T) {
+	palette := NewPalette(nil)
+	defer palette.Delete()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic")
+		}
+	}()
+	palette.SetColor(PaletteSize, color.White)
+}
@@ -56,3 +56,87 @@ func DefaultShader() *pancake.ShaderProgram {
 		return p
 	}
 }
+
+// MaskFragmentShader is a fragment shader for drawing stencil masks.
+// It discards fragments that are less than half opaque so that
+// sprites mask only their visible pixels.
+const MaskFragmentShader = `
+#version 330 core
+
+in vec2 f_Texture;
+in vec4 f_Color;
+
+out vec4 out_FragColor;
+
+uniform sampler2D u_Texture;
+
+void main()
+{
+	vec4 color = texture(u_Texture, f_Texture) * f_Color;
+	if (color.a < 0.5)
+		discard;
+	out_FragColor = color;
+}
+`
+
+var maskShader *pancake.ShaderProgram
+
+// MaskShader returns the shader program for drawing stencil masks.
+// It has the same uniforms as the default shader program.
+func MaskShader() *pancake.ShaderProgram {
+	if maskShader != nil {
+		return maskShader
+	} else if p, err := pancake.NewShaderProgram(DefaultVertexShader, MaskFragmentShader); err != nil {
+		panic(err)
+	} else {
+		maskShader = p
+		return p
+	}
+}
+
+// PaletteFragmentShader is a fragment shader for drawing indexed textures.
+// It looks up the index sampled from u_Texture in the Palette bound to u_Palette.
+// Indexed textures must use FilterNearest so that indices are not interpolated.
+const PaletteFragmentShader = `
+#version 330 core
+
+in vec2 f_Texture;
+in vec4 f_Color;
+
+out vec4 out_FragColor;
+
+uniform sampler2D u_Texture;
+uniform sampler2D u_Palette;
+
+void main()
+{
+	int index = int(texture(u_Texture, f_Texture).r * 255.0 + 0.5);
+	out_FragColor = texelFetch(u_Palette, ivec2(index, 0), 0) * f_Color;
+}
+`
+
+var paletteShader *pancake.ShaderProgram
+
+// PaletteShader returns the shader program for drawing indexed textures.
+// It has the same uniforms as the default shader program and
+// u_Palette, the texture unit that the Palette is bound to.
+//
+//	palette.BeginAt(1)
+//	shader := pancake2d.PaletteShader()
+//	shader.Begin()
+//	shader.SetUniform("u_Projection", projection)
+//	shader.SetUniform("u_Texture", 0)
+//	shader.SetUniform("u_Palette", 1)
+//	drawer.Draw(sprites)
+//	shader.End()
+//	palette.EndAt(1)
+func PaletteShader() *pancake.ShaderProgram {
+	if paletteShader != nil {
+		return paletteShader
+	} else if p, err := pancake.NewShaderProgram(DefaultVertexShader, PaletteFragmentShader); err != nil {
+		panic(err)
+	} else {
+		paletteShader = p
+		return p
+	}
+}
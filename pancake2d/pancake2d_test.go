@@ -1,24 +1,47 @@
 package pancake2d
 
 import (
+	"flag"
 	"fmt"
 	"image"
+	"image/color"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/askeladdk/pancake"
+	"github.com/askeladdk/pancake/mathx"
+	gl "github.com/askeladdk/pancake/opengl"
+	"github.com/askeladdk/pancake/pancaketest"
 	"golang.org/x/image/font/basicfont"
 )
 
+func init() {
+	flag.BoolVar(&pancaketest.Update, "update", false, "update golden files")
+}
+
 func TestMain(m *testing.M) {
 	pancake.Main(pancake.Options{
 		WindowSize: image.Point{320, 200},
+		Headless:   true,
 	}, func(_ pancake.App) error {
 		os.Exit(m.Run())
 		return nil
 	})
 }
 
+func TestRenderFrameViewport(t *testing.T) {
+	defer gl.Viewport(gl.GetViewport())
+
+	viewport := image.Rect(1, 2, 30, 40)
+	gl.Viewport(viewport)
+	if _, err := pancaketest.RenderFrame(image.Point{4, 4}, func() {}); err != nil {
+		t.Fatal(err)
+	} else if got := gl.GetViewport(); got != viewport {
+		t.Fatal(got)
+	}
+}
+
 func TestNewText(t *testing.T) {
 	font := pancake.NewFont(basicfont.Face7x13, pancake.ASCII)
 	text := NewText(font)
@@ -40,3 +63,219 @@ func TestNewText(t *testing.T) {
 		t.Fatal()
 	}
 }
+
+func TestStatsOverlay(t *testing.T) {
+	font := pancake.NewFont(basicfont.Face7x13, pancake.ASCII)
+	overlay := NewStatsOverlay(font)
+	overlay.Update(pancake.FrameStats{
+		DrawRate:   60,
+		UpdateRate: 60,
+		DrawCalls:  3,
+		Vertices:   36,
+	}, []time.Duration{
+		time.Second / 120,
+		time.Second / 40,
+		time.Second,
+	})
+
+	if overlay.Graph().Len() != 3 {
+		t.Fatal(overlay.Graph().Len())
+	} else if overlay.Text().Len() == 0 {
+		t.Fatal()
+	}
+
+	for i, c := range []color.Color{graphColorGood, graphColorSlow, graphColorBad} {
+		if overlay.Graph().TintColorAt(i) != c {
+			t.Fatal(i)
+		}
+	}
+}
+
+func TestSpriteDrawerMask(t *testing.T) {
+	white := pancake.NewTexture(image.Point{1, 1}, pancake.FilterNearest,
+		pancake.ColorFormatRGBA, []byte{255, 255, 255, 255})
+	defer white.Delete()
+
+	drawer := NewSpriteDrawer(4)
+	defer drawer.Delete()
+
+	projection := mathx.Ortho2D(0, 8, 8, 0)
+	quad := func(w float64) *graphBatch {
+		return &graphBatch{
+			texture:    white,
+			modelviews: []mathx.Aff3{mathx.ScaleAff3(mathx.Vec2{w, 8})},
+			colors:     []color.Color{color.RGBA{255, 255, 255, 255}},
+		}
+	}
+
+	for _, outside := range []bool{false, true} {
+		img, err := pancaketest.RenderFrame(image.Point{8, 8}, func() {
+			mask := drawer.Mask(quad(4), projection)
+			mask.Outside = outside
+			mask.Begin()
+			defer mask.End()
+
+			shader := DefaultShader()
+			shader.Begin()
+			defer shader.End()
+			shader.SetUniform("u_Projection", projection)
+			shader.SetUniform("u_Texture", 0)
+			drawer.Draw(quad(8))
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		left, right := img.RGBAAt(1, 4).A, img.RGBAAt(6, 4).A
+		if outside {
+			left, right = right, left
+		}
+		if left != 255 || right != 0 {
+			t.Fatal(outside, left, right)
+		}
+	}
+}
+
+func TestPaletteShader(t *testing.T) {
+	img := image.NewPaletted(image.Rect(0, 0, 2, 1), color.Palette{
+		color.RGBA{255, 0, 0, 255},
+		color.RGBA{0, 0, 255, 255},
+	})
+	img.Pix[1] = 1
+
+	tex := pancake.NewTextureFromImage(img, pancake.FilterNearest)
+	defer tex.Delete()
+	palette := pancake.NewPalette(img.Palette)
+	defer palette.Delete()
+
+	drawer := NewSpriteDrawer(1)
+	defer drawer.Delete()
+
+	projection := mathx.Ortho2D(0, 2, 1, 0)
+	batch := &graphBatch{
+		texture:    tex,
+		modelviews: []mathx.Aff3{mathx.ScaleAff3(mathx.Vec2{2, 1})},
+		colors:     []color.Color{color.RGBA{255, 255, 255, 255}},
+	}
+
+	render := func() *image.RGBA {
+		img, err := pancaketest.RenderFrame(image.Point{2, 1}, func() {
+			palette.BeginAt(1)
+			defer palette.EndAt(1)
+			shader := PaletteShader()
+			shader.Begin()
+			defer shader.End()
+			shader.SetUniform("u_Projection", projection)
+			shader.SetUniform("u_Texture", 0)
+			shader.SetUniform("u_Palette", 1)
+			drawer.Draw(batch)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return img
+	}
+
+	if img := render(); img.RGBAAt(0, 0).R != 255 || img.RGBAAt(1, 0).B != 255 {
+		t.Fatal(img.RGBAAt(0, 0), img.RGBAAt(1, 0))
+	}
+
+	palette.Cycle(0, 2, 1)
+	if img := render(); img.RGBAAt(0, 0).B != 255 || img.RGBAAt(1, 0).R != 255 {
+		t.Fatal(img.RGBAAt(0, 0), img.RGBAAt(1, 0))
+	}
+}
+
+func TestGrayAlphaTextures(t *testing.T) {
+	gray := image.NewGray(image.Rect(0, 0, 1, 1))
+	gray.Pix[0] = 128
+	alpha := image.NewAlpha(image.Rect(0, 0, 1, 1))
+	alpha.Pix[0] = 128
+
+	drawer := NewSpriteDrawer(1)
+	defer drawer.Delete()
+	projection := mathx.Ortho2D(0, 1, 1, 0)
+
+	for _, tc := range []struct {
+		img  image.Image
+		want color.RGBA
+	}{
+		{gray, color.RGBA{128, 128, 128, 255}},
+		{alpha, color.RGBA{128, 128, 128, 128}},
+	} {
+		tex := pancake.NewTextureFromImage(tc.img, pancake.FilterNearest)
+		img, err := pancaketest.RenderFrame(image.Point{1, 1}, func() {
+			shader := DefaultShader()
+			shader.Begin()
+			defer shader.End()
+			shader.SetUniform("u_Projection", projection)
+			shader.SetUniform("u_Texture", 0)
+			drawer.Draw(&graphBatch{
+				texture:    tex,
+				modelviews: []mathx.Aff3{mathx.ScaleAff3(mathx.Vec2{1, 1})},
+				colors:     []color.Color{color.RGBA{255, 255, 255, 255}},
+			})
+		})
+		tex.Delete()
+		if err != nil {
+			t.Fatal(err)
+		} else if got := img.RGBAAt(0, 0); got != tc.want {
+			t.Fatalf("%T: got %v, want %v", tc.img, got, tc.want)
+		}
+	}
+}
+
+// drawGolden draws a batch with the default shader into a frame of the given size.
+func drawGolden(t *testing.T, size image.Point, blend pancake.BlendMode, batch SpriteBatch) *image.RGBA {
+	drawer := NewSpriteDrawer(16)
+	defer drawer.Delete()
+
+	img, err := pancaketest.RenderFrame(size, func() {
+		blend.Begin()
+		defer blend.End()
+		shader := DefaultShader()
+		shader.Begin()
+		defer shader.End()
+		shader.SetUniform("u_Projection", mathx.Ortho2D(0, float64(size.X), float64(size.Y), 0))
+		shader.SetUniform("u_Texture", 0)
+		drawer.Draw(batch)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+func TestSpriteDrawerGolden(t *testing.T) {
+	// red, green, blue and white texels
+	tex := pancake.NewTexture(image.Point{2, 2}, pancake.FilterNearest, pancake.ColorFormatRGBA, []byte{
+		255, 0, 0, 255, 0, 255, 0, 255,
+		0, 0, 255, 255, 255, 255, 255, 255,
+	})
+	defer tex.Delete()
+
+	img := drawGolden(t, image.Point{16, 16}, pancake.BlendNone, &graphBatch{
+		texture: tex,
+		modelviews: []mathx.Aff3{
+			mathx.ScaleAff3(mathx.Vec2{8, 8}),
+			mathx.ScaleAff3(mathx.Vec2{8, 8}).Translated(mathx.Vec2{8, 8}),
+		},
+		colors: []color.Color{
+			color.RGBA{255, 255, 255, 255},
+			color.RGBA{128, 128, 128, 255},
+		},
+	})
+	pancaketest.AssertGolden(t, "sprites", img, 2)
+}
+
+func TestTextGolden(t *testing.T) {
+	font := pancake.NewFont(basicfont.Face7x13, pancake.ASCII)
+	defer font.Delete()
+
+	text := NewText(font)
+	text.Pos = mathx.Vec2{1, 1}
+	text.WriteString("Hi!\npan")
+
+	img := drawGolden(t, image.Point{24, 28}, pancake.BlendPremultiplied, text)
+	pancaketest.AssertGolden(t, "text", img, 2)
+}
@@ -108,6 +108,12 @@ func NewSpriteDrawer(capacity int) *SpriteDrawer {
 	}
 }
 
+// Delete deletes the vertex buffer of the SpriteDrawer.
+func (d *SpriteDrawer) Delete() {
+	d.vslice.Delete()
+	d.vbuffer.Delete()
+}
+
 // Draw renders a SpriteBatch.
 func (d *SpriteDrawer) Draw(batch SpriteBatch) {
 	if batch.Len() == 0 {
@@ -137,6 +143,22 @@ func (d *SpriteDrawer) Draw(batch SpriteBatch) {
 	d.vslice.End()
 }
 
+// Mask returns a StencilMask in the shape of the visible pixels of a SpriteBatch.
+// The mask is drawn with MaskShader and projection, which should be the
+// projection that the clipped sprites are drawn with.
+func (d *SpriteDrawer) Mask(batch SpriteBatch, projection mathx.Mat4) pancake.StencilMask {
+	return pancake.StencilMask{
+		Mask: func() {
+			shader := MaskShader()
+			shader.Begin()
+			defer shader.End()
+			shader.SetUniform("u_Projection", projection)
+			shader.SetUniform("u_Texture", 0)
+			d.Draw(batch)
+		},
+	}
+}
+
 func (d *SpriteDrawer) drawVertices(mode gl.Enum, verts []vertex) {
 	lo, step := 0, d.vslice.Len()
 	for hi := step; hi < len(verts); hi += step {
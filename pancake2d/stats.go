@@ -0,0 +1,147 @@
+package pancake2d
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"time"
+
+	"github.com/askeladdk/pancake"
+	"github.com/askeladdk/pancake/mathx"
+)
+
+// graphBatch draws the bars of the frame time graph.
+type graphBatch struct {
+	texture    *pancake.Texture
+	modelviews []mathx.Aff3
+	colors     []color.Color
+}
+
+// Len implements SpriteBatch.
+func (b *graphBatch) Len() int { return len(b.modelviews) }
+
+// TextureAt implements SpriteBatch.
+func (b *graphBatch) TextureAt(i int) *pancake.Texture { return b.texture }
+
+// TintColorAt implements SpriteBatch.
+func (b *graphBatch) TintColorAt(i int) color.Color { return b.colors[i] }
+
+// TextureRegionAt implements SpriteBatch.
+func (b *graphBatch) TextureRegionAt(i int) pancake.TextureRegion { return b.texture.TextureRegion() }
+
+// ModelViewAt implements SpriteBatch.
+func (b *graphBatch) ModelViewAt(i int) mathx.Aff3 { return b.modelviews[i] }
+
+// OriginAt implements SpriteBatch.
+func (b *graphBatch) OriginAt(i int) mathx.Vec2 { return mathx.Vec2{-.5, -.5} }
+
+// ZOrderAt implements SpriteBatch.
+func (b *graphBatch) ZOrderAt(i int) float64 { return 0 }
+
+var (
+	graphColorGood = color.RGBA{0, 192, 0, 192}
+	graphColorSlow = color.RGBA{192, 192, 0, 192}
+	graphColorBad  = color.RGBA{192, 0, 0, 192}
+)
+
+// StatsOverlay renders frame statistics and a frame time graph.
+// It is intended for development builds.
+type StatsOverlay struct {
+	// Pos is the top left position of the overlay.
+	Pos mathx.Vec2
+
+	// GraphSize is the size of the frame time graph in pixels.
+	GraphSize mathx.Vec2
+
+	// GraphScale is the frame time that corresponds to the full height of the graph.
+	GraphScale time.Duration
+
+	// TargetFrameTime is the frame time above which bars are drawn in warning colors.
+	TargetFrameTime time.Duration
+
+	text  *Text
+	graph graphBatch
+	times []time.Duration
+}
+
+// NewStatsOverlay creates a new StatsOverlay that renders text in the given Font.
+func NewStatsOverlay(font *pancake.Font) *StatsOverlay {
+	white := pancake.NewTexture(image.Point{1, 1}, pancake.FilterNearest,
+		pancake.ColorFormatRGBA, []byte{255, 255, 255, 255})
+	return &StatsOverlay{
+		GraphSize:       mathx.Vec2{pancake.FrameTimeWindow, 32},
+		GraphScale:      time.Second / 30,
+		TargetFrameTime: time.Second / 60,
+		text:            NewText(font),
+		graph:           graphBatch{texture: white},
+	}
+}
+
+// Delete deletes the graph texture. The Font is not deleted.
+func (o *StatsOverlay) Delete() {
+	o.graph.texture.Delete()
+}
+
+// Update rebuilds the overlay from the statistics and frame times reported by App.
+//
+//	overlay.Update(app.Stats(), app.FrameTimes(nil))
+func (o *StatsOverlay) Update(stats pancake.FrameStats, frameTimes []time.Duration) {
+	o.text.Reset()
+	o.text.Pos = o.Pos
+	fmt.Fprintf(o.text, "FPS %d UPS %d\n", stats.DrawRate, stats.UpdateRate)
+	fmt.Fprintf(o.text, "CPU %.2fms\n", milliseconds(stats.CPUTime))
+	fmt.Fprintf(o.text, "Frame %.2f/%.2f/%.2fms\n",
+		milliseconds(stats.MinFrameTime),
+		milliseconds(stats.AvgFrameTime),
+		milliseconds(stats.MaxFrameTime))
+	fmt.Fprintf(o.text, "Draws %d Verts %d\n", stats.DrawCalls, stats.Vertices)
+
+	o.times = append(o.times[:0], frameTimes...)
+	o.graph.modelviews = o.graph.modelviews[:0]
+	o.graph.colors = o.graph.colors[:0]
+
+	width, height := o.GraphSize.Elem()
+	barWidth := width / pancake.FrameTimeWindow
+	bottom := o.Pos.Add(mathx.Vec2{0, o.text.Dot[1] + height})
+
+	for i, d := range o.times {
+		h := height * float64(d) / float64(o.GraphScale)
+		if h > height {
+			h = height
+		}
+
+		o.graph.modelviews = append(o.graph.modelviews, mathx.
+			ScaleAff3(mathx.Vec2{barWidth, h}).
+			Translated(bottom.Add(mathx.Vec2{float64(i) * barWidth, -h})),
+		)
+
+		switch {
+		case d <= o.TargetFrameTime:
+			o.graph.colors = append(o.graph.colors, graphColorGood)
+		case d <= 2*o.TargetFrameTime:
+			o.graph.colors = append(o.graph.colors, graphColorSlow)
+		default:
+			o.graph.colors = append(o.graph.colors, graphColorBad)
+		}
+	}
+}
+
+// Text returns the text batch of the overlay.
+func (o *StatsOverlay) Text() *Text {
+	return o.text
+}
+
+// Graph returns the frame time graph batch of the overlay.
+func (o *StatsOverlay) Graph() SpriteBatch {
+	return &o.graph
+}
+
+// Draw draws the graph and text using a SpriteDrawer.
+func (o *StatsOverlay) Draw(d *SpriteDrawer) {
+	d.Draw(&o.graph)
+	d.Draw(o.text)
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -6,10 +6,15 @@ import (
 	"testing"
 )
 
+// testWindow is the window of the App that runs the tests.
+var testWindow *glfwWindow
+
 func TestMain(m *testing.M) {
 	Main(Options{
 		WindowSize: image.Point{320, 200},
-	}, func(_ App) error {
+		Headless:   true,
+	}, func(a App) error {
+		testWindow = a.(*app).glfwWindow
 		os.Exit(m.Run())
 		return nil
 	})
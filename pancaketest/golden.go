@@ -0,0 +1,144 @@
+// Package pancaketest implements utilities for testing rendered output.
+package pancaketest
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/askeladdk/pancake"
+	gl "github.com/askeladdk/pancake/opengl"
+)
+
+// Update makes AssertGolden overwrite the golden files instead of comparing against them.
+// Test packages usually set it from a flag:
+//
+//	func init() {
+//		flag.BoolVar(&pancaketest.Update, "update", false, "update golden files")
+//	}
+var Update bool
+
+// RenderFrame renders a single frame into an offscreen Framebuffer
+// of the given size and reads it back.
+// The framebuffer is cleared to transparent black before draw is called.
+// The framebuffer binding and the viewport are restored afterwards.
+func RenderFrame(size image.Point, draw func()) (*image.RGBA, error) {
+	fbo, err := pancake.NewFramebuffer(size, pancake.FilterNearest, true)
+	if err != nil {
+		return nil, err
+	}
+	defer fbo.Delete()
+
+	fbo.Begin()
+	defer fbo.End()
+
+	defer gl.Viewport(gl.GetViewport())
+	gl.Viewport(fbo.Bounds())
+	gl.ClearColor(0, 0, 0, 0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT | gl.STENCIL_BUFFER_BIT)
+	draw()
+
+	return fbo.Image(), nil
+}
+
+// Compare compares two images channel by channel.
+// Two pixels match if none of their channels differ by more than tolerance.
+// It returns a diff image in which matching pixels are dimmed
+// and mismatching pixels are colored red.
+// The diff image is nil if the images have different sizes.
+func Compare(got, want image.Image, tolerance uint8) (*image.RGBA, bool) {
+	gb, wb := got.Bounds(), want.Bounds()
+	if gb.Size() != wb.Size() {
+		return nil, false
+	}
+
+	diff := image.NewRGBA(image.Rectangle{Max: gb.Size()})
+	ok := true
+
+	for y := 0; y < gb.Dy(); y++ {
+		for x := 0; x < gb.Dx(); x++ {
+			c0 := color.NRGBAModel.Convert(got.At(gb.Min.X+x, gb.Min.Y+y)).(color.NRGBA)
+			c1 := color.NRGBAModel.Convert(want.At(wb.Min.X+x, wb.Min.Y+y)).(color.NRGBA)
+			if absDiff(c0.R, c1.R) > tolerance ||
+				absDiff(c0.G, c1.G) > tolerance ||
+				absDiff(c0.B, c1.B) > tolerance ||
+				absDiff(c0.A, c1.A) > tolerance {
+				diff.SetRGBA(x, y, color.RGBA{255, 0, 0, 255})
+				ok = false
+			} else {
+				gray := color.GrayModel.Convert(c1).(color.Gray).Y / 4
+				diff.SetRGBA(x, y, color.RGBA{gray, gray, gray, 255})
+			}
+		}
+	}
+
+	return diff, ok
+}
+
+// AssertGolden compares an image against the golden file testdata/name.png.
+// On mismatch the test fails and a diff image is written to testdata/name.diff.png.
+// If Update is set the golden file is overwritten instead.
+func AssertGolden(t testing.TB, name string, img image.Image, tolerance uint8) {
+	t.Helper()
+
+	golden := filepath.Join("testdata", name+".png")
+
+	if Update {
+		if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+			t.Fatal(err)
+		} else if err := writePNG(golden, img); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := readPNG(golden)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+
+	diff, ok := Compare(img, want, tolerance)
+	if ok {
+		return
+	} else if diff == nil {
+		t.Fatalf("%s: size %v does not match golden size %v",
+			name, img.Bounds().Size(), want.Bounds().Size())
+	}
+
+	diffPath := filepath.Join("testdata", name+".diff.png")
+	if err := writePNG(diffPath, diff); err != nil {
+		t.Fatal(err)
+	}
+	t.Fatalf("%s: image does not match golden file, see %s", name, diffPath)
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func readPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,32 @@
+package pancaketest
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	want := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	want.SetRGBA(0, 0, color.RGBA{100, 100, 100, 255})
+
+	got := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	got.SetRGBA(0, 0, color.RGBA{102, 100, 99, 255})
+
+	if _, ok := Compare(got, want, 2); !ok {
+		t.Fatal("expected match within tolerance")
+	}
+
+	diff, ok := Compare(got, want, 1)
+	if ok {
+		t.Fatal("expected mismatch")
+	} else if diff.RGBAAt(0, 0) != (color.RGBA{255, 0, 0, 255}) {
+		t.Fatal(diff.RGBAAt(0, 0))
+	} else if diff.RGBAAt(1, 1) == (color.RGBA{255, 0, 0, 255}) {
+		t.Fatal(diff.RGBAAt(1, 1))
+	}
+
+	if diff, ok := Compare(got, image.NewRGBA(image.Rect(0, 0, 3, 2)), 0); ok || diff != nil {
+		t.Fatal("expected size mismatch")
+	}
+}
@@ -0,0 +1,137 @@
+package pancake
+
+import (
+	"errors"
+	"runtime"
+	"time"
+
+	gl "github.com/askeladdk/pancake/opengl"
+)
+
+// QueryTarget is the quantity that a Query measures.
+type QueryTarget uint32
+
+const (
+	// QueryTimeElapsed measures the GPU time in nanoseconds.
+	QueryTimeElapsed QueryTarget = iota
+	// QuerySamplesPassed counts the samples that passed the depth test.
+	QuerySamplesPassed
+	// QueryPrimitivesGenerated counts the primitives emitted by the vertex stage.
+	QueryPrimitivesGenerated
+)
+
+func (target QueryTarget) param() gl.Enum {
+	switch target {
+	case QueryTimeElapsed:
+		return gl.TIME_ELAPSED
+	case QuerySamplesPassed:
+		return gl.SAMPLES_PASSED
+	case QueryPrimitivesGenerated:
+		return gl.PRIMITIVES_GENERATED
+	default:
+		panic(errors.New("invalid query target"))
+	}
+}
+
+// Query measures the GPU cost of the commands issued between Begin and End.
+// Results become available asynchronously a few frames later.
+// A Query can be begun again before the previous result is available,
+// in which case it allocates another GL query object instead of stalling.
+// Queries of the same target cannot be nested.
+type Query struct {
+	target  QueryTarget
+	active  gl.Query
+	pending []gl.Query
+	free    []gl.Query
+	result  uint64
+	ok      bool
+}
+
+// NewQuery creates a new Query.
+func NewQuery(target QueryTarget) *Query {
+	q := &Query{
+		target: target,
+	}
+	runtime.SetFinalizer(q, (*Query).finalize)
+	return q
+}
+
+// Begin starts measuring. It panics if the Query has already begun.
+func (q *Query) Begin() {
+	if q.active != 0 {
+		panic(errors.New("query already begun"))
+	}
+	q.poll()
+	if n := len(q.free); n > 0 {
+		q.active, q.free = q.free[n-1], q.free[:n-1]
+	} else {
+		q.active = gl.CreateQuery()
+		trackObject("query", uint32(q.active))
+	}
+	gl.BeginQuery(q.target.param(), q.active)
+}
+
+// End stops measuring. It panics if the Query has not begun.
+func (q *Query) End() {
+	if q.active == 0 {
+		panic(errors.New("query not begun"))
+	}
+	gl.EndQuery(q.target.param())
+	q.pending = append(q.pending, q.active)
+	q.active = 0
+}
+
+// Target returns the target of the Query.
+func (q *Query) Target() QueryTarget {
+	return q.target
+}
+
+// Result reports the most recent available result without stalling.
+// The boolean is false if no result has become available yet.
+func (q *Query) Result() (uint64, bool) {
+	q.poll()
+	return q.result, q.ok
+}
+
+// Duration reports the most recent result of a QueryTimeElapsed query.
+func (q *Query) Duration() (time.Duration, bool) {
+	result, ok := q.Result()
+	return time.Duration(result), ok
+}
+
+// poll collects all results that have become available.
+func (q *Query) poll() {
+	for len(q.pending) > 0 {
+		id := q.pending[0]
+		if gl.GetQueryObjecti(id, gl.QUERY_RESULT_AVAILABLE) == gl.FALSE {
+			return
+		}
+		q.result = gl.GetQueryObjectui64(id, gl.QUERY_RESULT)
+		q.ok = true
+		q.pending = q.pending[1:]
+		q.free = append(q.free, id)
+	}
+}
+
+// Delete deletes the GL query objects of the Query.
+// Results that are still pending are discarded.
+func (q *Query) Delete() {
+	runtime.SetFinalizer(q, nil)
+	q.delete()
+}
+
+func (q *Query) delete() {
+	ids := append(q.pending, q.free...)
+	if q.active != 0 {
+		ids = append(ids, q.active)
+	}
+	for _, id := range ids {
+		untrackObject("query", uint32(id))
+		gl.DeleteQuery(id)
+	}
+	q.pending, q.free, q.active = nil, nil, 0
+}
+
+func (q *Query) finalize() {
+	queueCall(q.delete)
+}
@@ -0,0 +1,112 @@
+package pancake
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	gl "github.com/askeladdk/pancake/opengl"
+)
+
+func TestQuery(t *testing.T) {
+	q := NewQuery(QuerySamplesPassed)
+	defer q.Delete()
+
+	wait := func() {
+		for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(time.Millisecond) {
+			if result, ok := q.Result(); ok {
+				if result != 0 {
+					t.Fatal(result)
+				}
+				return
+			}
+		}
+		t.Fatal("no result")
+	}
+
+	// a query object is reused once its result has been collected
+	var id gl.Query
+	for i := 0; i < 3; i++ {
+		q.Begin()
+		if i == 0 {
+			id = q.active
+		} else if q.active != id {
+			t.Fatal(i, q.active, id)
+		}
+		q.End()
+		if len(q.pending) != 1 || len(q.free) != 0 {
+			t.Fatal(i, len(q.pending), len(q.free))
+		}
+
+		wait()
+		if len(q.pending) != 0 || len(q.free) != 1 {
+			t.Fatal(i, len(q.pending), len(q.free))
+		}
+	}
+}
+
+func TestQuerySamplesPassed(t *testing.T) {
+	fbo, err := NewFramebuffer(image.Point{8, 8}, FilterNearest, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fbo.Delete()
+
+	shader, err := NewShaderProgram(`
+#version 330 core
+layout(location = 0) in vec2 in_Position;
+void main() { gl_Position = vec4(in_Position, 0, 1); }
+`, `
+#version 330 core
+out vec4 out_FragColor;
+void main() { out_FragColor = vec4(1); }
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shader.Delete()
+
+	vbo := NewVertexBuffer(upscaleQuadFormat, 4, upscaleQuadVertices)
+	defer vbo.Delete()
+	quad := NewVertexArraySlice(vbo)
+	defer quad.Delete()
+
+	q := NewQuery(QuerySamplesPassed)
+	defer q.Delete()
+
+	fbo.Begin()
+	gl.Viewport(fbo.Bounds())
+	shader.Begin()
+	quad.Begin()
+	q.Begin()
+	quad.Draw(gl.TRIANGLE_STRIP)
+	q.End()
+	quad.End()
+	shader.End()
+	fbo.End()
+
+	// the full screen quad covers every pixel once
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(time.Millisecond) {
+		if result, ok := q.Result(); ok {
+			if result != 64 {
+				t.Fatal(result)
+			}
+			return
+		}
+	}
+	t.Fatal("no result")
+}
+
+func TestQueryNested(t *testing.T) {
+	q := NewQuery(QueryPrimitivesGenerated)
+	defer q.Delete()
+
+	q.Begin()
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic")
+		}
+		q.End()
+	}()
+	q.Begin()
+}
@@ -11,8 +11,21 @@ type renderbuffer struct {
 	id gl.Renderbuffer
 }
 
+func (rbo *renderbuffer) Delete() {
+	if rbo.id != 0 {
+		runtime.SetFinalizer(rbo, nil)
+		rbo.delete()
+	}
+}
+
 func (rbo *renderbuffer) delete() {
+	untrackObject("renderbuffer", uint32(rbo.id))
 	gl.DeleteRenderbuffer(rbo.id)
+	rbo.id = 0
+}
+
+func (rbo *renderbuffer) finalize() {
+	queueCall(rbo.delete)
 }
 
 func newRenderbuffer(size image.Point, internalFormat gl.Enum, samples int) *renderbuffer {
@@ -20,7 +33,8 @@ func newRenderbuffer(size image.Point, internalFormat gl.Enum, samples int) *ren
 		id: gl.CreateRenderbuffer(),
 	}
 
-	runtime.SetFinalizer(rbo, (*renderbuffer).delete)
+	trackObject("renderbuffer", uint32(rbo.id))
+	runtime.SetFinalizer(rbo, (*renderbuffer).finalize)
 
 	gl.BindRenderbuffer(rbo.id)
 	defer gl.BindRenderbuffer(0)
@@ -0,0 +1,417 @@
+package pancake
+
+import (
+	"errors"
+
+	gl "github.com/askeladdk/pancake/opengl"
+)
+
+// BlendFactor is a source or destination blending factor.
+type BlendFactor int
+
+const (
+	BlendZero BlendFactor = iota
+	BlendOne
+	BlendSrcColor
+	BlendOneMinusSrcColor
+	BlendDstColor
+	BlendOneMinusDstColor
+	BlendSrcAlpha
+	BlendOneMinusSrcAlpha
+	BlendDstAlpha
+	BlendOneMinusDstAlpha
+)
+
+func (f BlendFactor) param() gl.Enum {
+	switch f {
+	case BlendZero:
+		return gl.ZERO
+	case BlendOne:
+		return gl.ONE
+	case BlendSrcColor:
+		return gl.SRC_COLOR
+	case BlendOneMinusSrcColor:
+		return gl.ONE_MINUS_SRC_COLOR
+	case BlendDstColor:
+		return gl.DST_COLOR
+	case BlendOneMinusDstColor:
+		return gl.ONE_MINUS_DST_COLOR
+	case BlendSrcAlpha:
+		return gl.SRC_ALPHA
+	case BlendOneMinusSrcAlpha:
+		return gl.ONE_MINUS_SRC_ALPHA
+	case BlendDstAlpha:
+		return gl.DST_ALPHA
+	case BlendOneMinusDstAlpha:
+		return gl.ONE_MINUS_DST_ALPHA
+	default:
+		panic(errors.New("invalid blend factor"))
+	}
+}
+
+// BlendEquation combines the weighted source and destination colors.
+type BlendEquation int
+
+const (
+	BlendAdd BlendEquation = iota
+	BlendSubtract
+	BlendReverseSubtract
+	BlendMin
+	BlendMax
+)
+
+func (eq BlendEquation) param() gl.Enum {
+	switch eq {
+	case BlendAdd:
+		return gl.FUNC_ADD
+	case BlendSubtract:
+		return gl.FUNC_SUBTRACT
+	case BlendReverseSubtract:
+		return gl.FUNC_REVERSE_SUBTRACT
+	case BlendMin:
+		return gl.MIN
+	case BlendMax:
+		return gl.MAX
+	default:
+		panic(errors.New("invalid blend equation"))
+	}
+}
+
+// BlendMode determines how fragments are blended with the framebuffer.
+// Blend modes are internally placed in a stack like Scissor. Begin() and End() pairs must
+// always be balanced for the stack to work properly.
+//
+//	pancake.BlendAlpha.Begin()
+//	... (draw translucent sprites)
+//	pancake.BlendAlpha.End()
+//
+// The zero value BlendNone disables blending.
+type BlendMode struct {
+	SrcRGB, DstRGB     BlendFactor
+	SrcAlpha, DstAlpha BlendFactor
+	EquationRGB        BlendEquation
+	EquationAlpha      BlendEquation
+}
+
+var (
+	// BlendNone disables blending.
+	BlendNone = BlendMode{}
+
+	// BlendAlpha blends straight (non-premultiplied) alpha.
+	BlendAlpha = BlendMode{
+		SrcRGB: BlendSrcAlpha, DstRGB: BlendOneMinusSrcAlpha,
+		SrcAlpha: BlendOne, DstAlpha: BlendOneMinusSrcAlpha,
+	}
+
+	// BlendPremultiplied blends premultiplied alpha.
+	BlendPremultiplied = BlendMode{
+		SrcRGB: BlendOne, DstRGB: BlendOneMinusSrcAlpha,
+		SrcAlpha: BlendOne, DstAlpha: BlendOneMinusSrcAlpha,
+	}
+
+	// BlendAdditive adds the source to the destination weighted by the source alpha.
+	BlendAdditive = BlendMode{
+		SrcRGB: BlendSrcAlpha, DstRGB: BlendOne,
+		SrcAlpha: BlendZero, DstAlpha: BlendOne,
+	}
+
+	// BlendMultiply multiplies the source with the destination.
+	BlendMultiply = BlendMode{
+		SrcRGB: BlendDstColor, DstRGB: BlendOneMinusSrcAlpha,
+		SrcAlpha: BlendDstAlpha, DstAlpha: BlendOneMinusSrcAlpha,
+	}
+
+	// BlendScreen inverts, multiplies and inverts the source and destination,
+	// which brightens the destination.
+	BlendScreen = BlendMode{
+		SrcRGB: BlendOne, DstRGB: BlendOneMinusSrcColor,
+		SrcAlpha: BlendOne, DstAlpha: BlendOneMinusSrcAlpha,
+	}
+)
+
+// Begin applies the blend mode.
+func (b BlendMode) Begin() {
+	blendstack.push(b)
+}
+
+// End re-applies the previous blend mode.
+func (b BlendMode) End() {
+	blendstack.pop()
+}
+
+type blendStack struct {
+	stack   []BlendMode
+	current BlendMode
+	enabled bool
+	// funcs is the last blend function that was set, which persists while blending is disabled.
+	funcs BlendMode
+}
+
+var blendstack = &blendStack{
+	funcs: BlendMode{SrcRGB: BlendOne, SrcAlpha: BlendOne},
+}
+
+func (bs *blendStack) setBlendMode(mode BlendMode) {
+	if enabled := mode != BlendNone; enabled != bs.enabled {
+		if enabled {
+			gl.Enable(gl.BLEND)
+		} else {
+			gl.Disable(gl.BLEND)
+		}
+		bs.enabled = enabled
+	}
+
+	if bs.enabled {
+		if mode.SrcRGB != bs.funcs.SrcRGB || mode.DstRGB != bs.funcs.DstRGB ||
+			mode.SrcAlpha != bs.funcs.SrcAlpha || mode.DstAlpha != bs.funcs.DstAlpha {
+			gl.BlendFuncSeparate(mode.SrcRGB.param(), mode.DstRGB.param(),
+				mode.SrcAlpha.param(), mode.DstAlpha.param())
+		}
+		if mode.EquationRGB != bs.funcs.EquationRGB || mode.EquationAlpha != bs.funcs.EquationAlpha {
+			gl.BlendEquationSeparate(mode.EquationRGB.param(), mode.EquationAlpha.param())
+		}
+		bs.funcs = mode
+	}
+
+	bs.current = mode
+}
+
+func (bs *blendStack) push(next BlendMode) {
+	bs.stack = append(bs.stack, bs.current)
+	bs.setBlendMode(next)
+}
+
+func (bs *blendStack) pop() {
+	prev := bs.stack[len(bs.stack)-1]
+	bs.stack = bs.stack[:len(bs.stack)-1]
+	bs.setBlendMode(prev)
+}
+
+// CompareFunc compares an incoming value against a stored value.
+type CompareFunc int
+
+const (
+	CompareLess CompareFunc = iota
+	CompareLessEqual
+	CompareEqual
+	CompareNotEqual
+	CompareGreater
+	CompareGreaterEqual
+	CompareAlways
+	CompareNever
+)
+
+func (fn CompareFunc) param() gl.Enum {
+	switch fn {
+	case CompareLess:
+		return gl.LESS
+	case CompareLessEqual:
+		return gl.LEQUAL
+	case CompareEqual:
+		return gl.EQUAL
+	case CompareNotEqual:
+		return gl.NOTEQUAL
+	case CompareGreater:
+		return gl.GREATER
+	case CompareGreaterEqual:
+		return gl.GEQUAL
+	case CompareAlways:
+		return gl.ALWAYS
+	case CompareNever:
+		return gl.NEVER
+	default:
+		panic(errors.New("invalid compare func"))
+	}
+}
+
+// DepthState configures the depth test.
+// Depth states are internally placed in a stack like Scissor. Begin() and End() pairs must
+// always be balanced for the stack to work properly.
+//
+// The zero value DepthNone disables the depth test.
+type DepthState struct {
+	// Test enables the depth test.
+	Test bool
+	// Func passes fragments whose depth compares successfully to the stored depth.
+	Func CompareFunc
+	// Write enables writing to the depth buffer.
+	// It is ignored while the test is disabled, which leaves writing enabled
+	// so that the depth buffer can be cleared.
+	Write bool
+}
+
+var (
+	// DepthNone disables the depth test.
+	DepthNone = DepthState{}
+
+	// DepthLess draws fragments that are nearer than the stored depth and writes their depth.
+	DepthLess = DepthState{Test: true, Func: CompareLess, Write: true}
+
+	// DepthReadOnly tests against the stored depth without writing to it.
+	DepthReadOnly = DepthState{Test: true, Func: CompareLessEqual}
+)
+
+// Begin applies the depth state.
+func (ds DepthState) Begin() {
+	depthstack.push(ds)
+}
+
+// End re-applies the previous depth state.
+func (ds DepthState) End() {
+	depthstack.pop()
+}
+
+type depthStack struct {
+	stack []DepthState
+	// current is the state of the GL, which retains Func and Write while the test is disabled.
+	current DepthState
+}
+
+var depthstack = &depthStack{
+	current: DepthState{Func: CompareLess, Write: true},
+}
+
+func (ds *depthStack) setDepthState(state DepthState) {
+	if state.Test != ds.current.Test {
+		if state.Test {
+			gl.Enable(gl.DEPTH_TEST)
+		} else {
+			gl.Disable(gl.DEPTH_TEST)
+		}
+		ds.current.Test = state.Test
+	}
+
+	if state.Test && state.Func != ds.current.Func {
+		gl.DepthFunc(state.Func.param())
+		ds.current.Func = state.Func
+	}
+
+	// glClear respects the depth mask even if the depth test is disabled
+	write := state.Write || !state.Test
+	if write != ds.current.Write {
+		gl.DepthMask(write)
+		ds.current.Write = write
+	}
+}
+
+func (ds *depthStack) push(next DepthState) {
+	prev := ds.current
+	if !prev.Test {
+		prev = DepthNone
+	}
+	ds.stack = append(ds.stack, prev)
+	ds.setDepthState(next)
+}
+
+func (ds *depthStack) pop() {
+	prev := ds.stack[len(ds.stack)-1]
+	ds.stack = ds.stack[:len(ds.stack)-1]
+	ds.setDepthState(prev)
+}
+
+// CullFace selects the faces that are culled.
+type CullFace int
+
+const (
+	CullNone CullFace = iota
+	CullBack
+	CullFront
+	CullFrontAndBack
+)
+
+func (face CullFace) param() gl.Enum {
+	switch face {
+	case CullBack:
+		return gl.BACK
+	case CullFront:
+		return gl.FRONT
+	case CullFrontAndBack:
+		return gl.FRONT_AND_BACK
+	default:
+		panic(errors.New("invalid cull face"))
+	}
+}
+
+// Winding is the vertex order of front facing polygons.
+type Winding int
+
+const (
+	CounterClockwise Winding = iota
+	Clockwise
+)
+
+func (w Winding) param() gl.Enum {
+	switch w {
+	case CounterClockwise:
+		return gl.CCW
+	case Clockwise:
+		return gl.CW
+	default:
+		panic(errors.New("invalid winding"))
+	}
+}
+
+// CullState configures face culling.
+// Cull states are internally placed in a stack like Scissor. Begin() and End() pairs must
+// always be balanced for the stack to work properly.
+//
+// The zero value disables culling.
+type CullState struct {
+	// Face selects the faces to cull.
+	Face CullFace
+	// Front is the winding of front facing polygons.
+	Front Winding
+}
+
+// Begin applies the cull state.
+func (cs CullState) Begin() {
+	cullstack.push(cs)
+}
+
+// End re-applies the previous cull state.
+func (cs CullState) End() {
+	cullstack.pop()
+}
+
+type cullStack struct {
+	stack   []CullState
+	current CullState
+	// face is the cull face of the GL, which persists while culling is disabled.
+	face CullFace
+}
+
+var cullstack = &cullStack{
+	face: CullBack,
+}
+
+func (cs *cullStack) setCullState(state CullState) {
+	if enabled := state.Face != CullNone; enabled != (cs.current.Face != CullNone) {
+		if enabled {
+			gl.Enable(gl.CULL_FACE)
+		} else {
+			gl.Disable(gl.CULL_FACE)
+		}
+	}
+
+	if state.Face != CullNone && state.Face != cs.face {
+		gl.CullFace(state.Face.param())
+		cs.face = state.Face
+	}
+
+	if state.Front != cs.current.Front {
+		gl.FrontFace(state.Front.param())
+	}
+
+	cs.current = state
+}
+
+func (cs *cullStack) push(next CullState) {
+	cs.stack = append(cs.stack, cs.current)
+	cs.setCullState(next)
+}
+
+func (cs *cullStack) pop() {
+	prev := cs.stack[len(cs.stack)-1]
+	cs.stack = cs.stack[:len(cs.stack)-1]
+	cs.setCullState(prev)
+}
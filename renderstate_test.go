@@ -0,0 +1,58 @@
+package pancake
+
+import (
+	"testing"
+
+	gl "github.com/askeladdk/pancake/opengl"
+)
+
+func TestBlendStack(t *testing.T) {
+	BlendPremultiplied.Begin()
+	BlendAdditive.Begin()
+	if gl.GetInteger(gl.BLEND) != gl.TRUE || gl.GetInteger(gl.BLEND_DST_RGB) != gl.ONE {
+		t.Fatal("additive")
+	}
+	BlendAdditive.End()
+	if gl.GetInteger(gl.BLEND_SRC_RGB) != gl.ONE || gl.GetInteger(gl.BLEND_DST_RGB) != gl.ONE_MINUS_SRC_ALPHA {
+		t.Fatal("premultiplied")
+	}
+	BlendPremultiplied.End()
+	if gl.GetInteger(gl.BLEND) != gl.FALSE {
+		t.Fatal("none")
+	}
+}
+
+func TestDepthStack(t *testing.T) {
+	DepthLess.Begin()
+	DepthReadOnly.Begin()
+	if gl.GetInteger(gl.DEPTH_FUNC) != gl.LEQUAL || gl.GetInteger(gl.DEPTH_WRITEMASK) != gl.FALSE {
+		t.Fatal("read only")
+	}
+	DepthReadOnly.End()
+	if gl.GetInteger(gl.DEPTH_FUNC) != gl.LESS || gl.GetInteger(gl.DEPTH_WRITEMASK) != gl.TRUE {
+		t.Fatal("less")
+	}
+	DepthLess.End()
+	if gl.GetInteger(gl.DEPTH_TEST) != gl.FALSE {
+		t.Fatal("none")
+	}
+
+	// popping a read only state leaves the depth buffer clearable
+	DepthReadOnly.Begin()
+	DepthReadOnly.End()
+	if gl.GetInteger(gl.DEPTH_TEST) != gl.FALSE || gl.GetInteger(gl.DEPTH_WRITEMASK) != gl.TRUE {
+		t.Fatal("none after read only")
+	}
+}
+
+func TestCullStack(t *testing.T) {
+	cull := CullState{Face: CullFront, Front: Clockwise}
+	cull.Begin()
+	if gl.GetInteger(gl.CULL_FACE) != gl.TRUE || gl.GetInteger(gl.CULL_FACE_MODE) != gl.FRONT || gl.GetInteger(gl.FRONT_FACE) != gl.CW {
+		t.Fatal("front")
+	}
+	cull.End()
+	if gl.GetInteger(gl.CULL_FACE) != gl.FALSE || gl.GetInteger(gl.FRONT_FACE) != gl.CCW {
+		t.Fatal("none")
+	}
+}
@@ -0,0 +1,174 @@
+package pancake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// ReplayEndEvent is emitted once when all events of Options.ReplayInput have been replayed.
+type ReplayEndEvent struct{}
+
+// replayableEvents are the input events that can be recorded and replayed.
+// ResizeEvents are not replayed because they must match the live window.
+var replayableEvents = func(events ...interface{}) map[string]reflect.Type {
+	types := map[string]reflect.Type{}
+	for _, event := range events {
+		t := reflect.TypeOf(event)
+		types[t.Name()] = t
+	}
+	return types
+}(
+	CloseEvent{},
+	CharEvent{},
+	KeyEvent{},
+	MouseEvent{},
+	MouseMoveEvent{},
+	ScrollEvent{},
+	CursorEnterEvent{},
+	FocusEvent{},
+	IconifyEvent{},
+	GamepadConnectEvent{},
+	GamepadDisconnectEvent{},
+	GamepadButtonEvent{},
+	GamepadAxisEvent{},
+)
+
+// frameEventType is the type of the records that hold the DeltaTime of every FrameEvent.
+const frameEventType = "FrameEvent"
+
+// recordedEvent is the serialized form of an input event.
+// Recordings are stored as a stream of JSON objects, one per line.
+type recordedEvent struct {
+	Frame int             `json:"frame"`
+	Type  string          `json:"type"`
+	Event json.RawMessage `json:"event"`
+}
+
+type inputRecorder struct {
+	enc *json.Encoder
+	err error
+}
+
+func newInputRecorder(w io.Writer) *inputRecorder {
+	return &inputRecorder{
+		enc: json.NewEncoder(w),
+	}
+}
+
+// record writes an event tagged with the frame index.
+// Only the first error is kept.
+func (r *inputRecorder) record(frame int, event interface{}) {
+	if r.err != nil {
+		return
+	}
+
+	t := reflect.TypeOf(event)
+	if _, ok := replayableEvents[t.Name()]; ok {
+		r.write(frame, t.Name(), event)
+	}
+}
+
+// recordFrame writes the DeltaTime of a FrameEvent so that a replay
+// steps the same amount of time every frame regardless of the LoopMode.
+func (r *inputRecorder) recordFrame(frame int, deltaTime float64) {
+	r.write(frame, frameEventType, FrameEvent{deltaTime})
+}
+
+func (r *inputRecorder) write(frame int, typ string, event interface{}) {
+	if r.err != nil {
+		return
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		r.err = err
+		return
+	}
+
+	r.err = r.enc.Encode(recordedEvent{
+		Frame: frame,
+		Type:  typ,
+		Event: data,
+	})
+}
+
+type inputReplay struct {
+	frames     []int
+	events     []interface{}
+	deltaTimes map[int]float64
+	pos        int
+	ended      bool
+}
+
+// readInputReplay decodes a recording made by inputRecorder.
+func readInputReplay(r io.Reader) (*inputReplay, error) {
+	replay := inputReplay{
+		deltaTimes: map[int]float64{},
+	}
+	dec := json.NewDecoder(r)
+	for {
+		var rec recordedEvent
+		if err := dec.Decode(&rec); err == io.EOF {
+			return &replay, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		if rec.Type == frameEventType {
+			var event FrameEvent
+			if err := json.Unmarshal(rec.Event, &event); err != nil {
+				return nil, err
+			}
+			replay.deltaTimes[rec.Frame] = event.DeltaTime
+			continue
+		}
+
+		t, ok := replayableEvents[rec.Type]
+		if !ok {
+			return nil, fmt.Errorf("replay: unknown event type %q", rec.Type)
+		}
+
+		event := reflect.New(t)
+		if err := json.Unmarshal(rec.Event, event.Interface()); err != nil {
+			return nil, err
+		}
+
+		replay.frames = append(replay.frames, rec.Frame)
+		replay.events = append(replay.events, event.Elem().Interface())
+	}
+}
+
+// replace substitutes the live input events of a frame with the recorded ones.
+// Live CloseEvents are kept so that the window can still be closed
+// and live ResizeEvents are kept so that the viewport follows the window.
+func (r *inputReplay) replace(frame int, live []interface{}) []interface{} {
+	events := live[:0]
+	for _, event := range live {
+		switch event.(type) {
+		case CloseEvent, ResizeEvent:
+			events = append(events, event)
+		}
+	}
+
+	for ; r.pos < len(r.events) && r.frames[r.pos] <= frame; r.pos++ {
+		events = append(events, r.events[r.pos])
+	}
+
+	if r.pos == len(r.events) && !r.ended {
+		r.ended = true
+		events = append(events, ReplayEndEvent{})
+	}
+
+	return events
+}
+
+// deltaTime returns the recorded DeltaTime of a frame,
+// or the live one if the recording has none.
+func (r *inputReplay) deltaTime(frame int, live float64) float64 {
+	if deltaTime, ok := r.deltaTimes[frame]; ok {
+		return deltaTime
+	}
+	return live
+}
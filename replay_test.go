@@ -0,0 +1,111 @@
+package pancake
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"reflect"
+	"testing"
+)
+
+func TestInputReplay(t *testing.T) {
+	var buf bytes.Buffer
+	rec := newInputRecorder(&buf)
+	rec.record(0, KeyEvent{Key: KeyA, Modifiers: ModPressed | ModShift, Scancode: 38})
+	rec.record(0, FrameEvent{DeltaTime: 1})
+	rec.record(0, ResizeEvent{Size: image.Pt(1, 1)})
+	rec.recordFrame(1, 0.25)
+	rec.record(2, MouseMoveEvent{Position: image.Pt(3, 4)})
+	rec.record(2, ScrollEvent{X: 0, Y: -1.5, Position: image.Pt(3, 4)})
+	if rec.err != nil {
+		t.Fatal(rec.err)
+	}
+
+	replay, err := readInputReplay(&buf)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(replay.events) != 3 {
+		t.Fatal(len(replay.events))
+	}
+
+	if dt := replay.deltaTime(1, 1); dt != 0.25 {
+		t.Fatal(dt)
+	} else if dt := replay.deltaTime(2, 1); dt != 1 {
+		t.Fatal(dt)
+	}
+
+	resize := ResizeEvent{Size: image.Pt(2, 2)}
+	events := replay.replace(0, []interface{}{CharEvent{'x'}, CloseEvent{}, resize})
+	if len(events) != 3 || events[0] != (CloseEvent{}) || events[1] != resize {
+		t.Fatal(events)
+	} else if events[2] != (KeyEvent{Key: KeyA, Modifiers: ModPressed | ModShift, Scancode: 38}) {
+		t.Fatal(events[2])
+	}
+
+	if events := replay.replace(1, nil); len(events) != 0 {
+		t.Fatal(events)
+	}
+
+	events = replay.replace(2, nil)
+	if len(events) != 3 {
+		t.Fatal(events)
+	} else if events[0] != (MouseMoveEvent{Position: image.Pt(3, 4)}) {
+		t.Fatal(events[0])
+	} else if events[2] != (ReplayEndEvent{}) {
+		t.Fatal(events[2])
+	}
+
+	if events := replay.replace(3, nil); len(events) != 0 {
+		t.Fatal(events)
+	}
+
+	if _, err := readInputReplay(bytes.NewBufferString(`{"frame":0,"type":"Bogus","event":{}}`)); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestInputReplayLoopVariable(t *testing.T) {
+	want := []float64{0.5, 0.25, 0.125}
+
+	var buf bytes.Buffer
+	rec := newInputRecorder(&buf)
+	for frame, dt := range want {
+		rec.recordFrame(frame, dt)
+	}
+	replay, err := readInputReplay(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := &app{
+		glfwWindow:   testWindow,
+		deltaTime:    1. / 60,
+		drawDelta:    1. / 60,
+		loopMode:     LoopVariable,
+		maxFrameSkip: 5,
+		eventch:      make(chan interface{}),
+		replay:       replay,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		a.loop(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// the replayed frames step the recorded time instead of the elapsed time
+	var got []float64
+	for len(got) < len(want) {
+		if event, ok := (<-a.Events()).(FrameEvent); ok {
+			got = append(got, event.DeltaTime)
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatal(got)
+	}
+}
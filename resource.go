@@ -0,0 +1,139 @@
+package pancake
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// GLObject identifies an OpenGL object owned by a resource.
+type GLObject struct {
+	Kind string
+	ID   uint32
+}
+
+func (obj GLObject) String() string {
+	return fmt.Sprintf("%s %d", obj.Kind, obj.ID)
+}
+
+// objects tracks live GL objects and the calls that are waiting to run on the goroutine that runs the App.
+var objects = struct {
+	mu    sync.Mutex
+	live  map[GLObject]struct{}
+	queue []func()
+}{
+	live: map[GLObject]struct{}{},
+}
+
+func trackObject(kind string, id uint32) {
+	objects.mu.Lock()
+	objects.live[GLObject{kind, id}] = struct{}{}
+	objects.mu.Unlock()
+}
+
+func untrackObject(kind string, id uint32) {
+	objects.mu.Lock()
+	delete(objects.live, GLObject{kind, id})
+	objects.mu.Unlock()
+}
+
+// queueCall defers a call to the goroutine that runs the App.
+// Finalizers use it to delete their GL objects because they run
+// at arbitrary times on arbitrary goroutines.
+func queueCall(fn func()) {
+	objects.mu.Lock()
+	objects.queue = append(objects.queue, fn)
+	objects.mu.Unlock()
+}
+
+// flushRequested is set once per iteration of the event loop.
+var flushRequested int32
+
+// requestFlush makes the next binding of a resource flush the queued calls,
+// so that they run even if the App does not end frames.
+// The event loop cannot flush by itself because it does not own the GL context.
+func requestFlush() {
+	atomic.StoreInt32(&flushRequested, 1)
+}
+
+// flushRequestedCalls runs the queued calls if the event loop has requested it.
+func flushRequestedCalls() {
+	if atomic.CompareAndSwapInt32(&flushRequested, 1, 0) {
+		flushCalls()
+	}
+}
+
+// flushCalls runs the queued calls on the goroutine that runs the App.
+func flushCalls() {
+	objects.mu.Lock()
+	queue := objects.queue
+	objects.queue = nil
+	objects.mu.Unlock()
+
+	for _, fn := range queue {
+		fn()
+	}
+}
+
+// collectDeletes runs a garbage collection, waits for the finalizers of
+// unreachable resources to queue their deletes and runs the deletes.
+func collectDeletes() {
+	// finalizers run asynchronously on a single goroutine after the collection,
+	// so a sentinel finalized in a second collection runs after the others
+	runtime.GC()
+	done := make(chan struct{})
+	sentinel := new([32]byte)
+	runtime.SetFinalizer(sentinel, func(*[32]byte) { close(done) })
+	sentinel = nil
+	runtime.GC()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+	}
+
+	flushCalls()
+}
+
+// LiveObjects reports the GL objects that have been created and not yet deleted,
+// either explicitly or by the garbage collector.
+func LiveObjects() []GLObject {
+	objects.mu.Lock()
+	list := make([]GLObject, 0, len(objects.live))
+	for obj := range objects.live {
+		list = append(list, obj)
+	}
+	objects.mu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Kind != list[j].Kind {
+			return list[i].Kind < list[j].Kind
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// leakReport describes the GL objects that are still alive at shutdown.
+func leakReport() (DebugMessage, bool) {
+	live := LiveObjects()
+	if len(live) == 0 {
+		return DebugMessage{}, false
+	}
+
+	names := make([]string, len(live))
+	for i, obj := range live {
+		names[i] = obj.String()
+	}
+
+	return DebugMessage{
+		Source:   DebugSourceApplication,
+		Type:     DebugTypeOther,
+		Severity: DebugSeverityMedium,
+		Message:  fmt.Sprintf("%d GL objects alive at shutdown: %s", len(live), strings.Join(names, ", ")),
+	}, true
+}
@@ -0,0 +1,105 @@
+package pancake
+
+import (
+	"image"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeleteQueue(t *testing.T) {
+	trackObject("test", 2)
+	trackObject("test", 1)
+
+	var live []GLObject
+	for _, obj := range LiveObjects() {
+		if obj.Kind == "test" {
+			live = append(live, obj)
+		}
+	}
+	if len(live) != 2 || live[0].ID != 1 || live[1].ID != 2 {
+		t.Fatal(live)
+	}
+
+	if m, ok := leakReport(); !ok || !strings.Contains(m.Message, "test 1, test 2") {
+		t.Fatal(m)
+	}
+
+	queueCall(func() { untrackObject("test", 1) })
+	queueCall(func() { untrackObject("test", 2) })
+	flushCalls()
+
+	for _, obj := range LiveObjects() {
+		if obj.Kind == "test" {
+			t.Fatal(obj)
+		}
+	}
+}
+
+func TestCollectDeletes(t *testing.T) {
+	func() {
+		obj := new([32]byte)
+		trackObject("collect", 1)
+		runtime.SetFinalizer(obj, func(*[32]byte) {
+			queueCall(func() { untrackObject("collect", 1) })
+		})
+	}()
+
+	collectDeletes()
+
+	for _, obj := range LiveObjects() {
+		if obj.Kind == "collect" {
+			t.Fatal(obj)
+		}
+	}
+}
+
+func TestRequestFlush(t *testing.T) {
+	b := newBinder(func(uint32) {})
+	flushRequestedCalls() // the request of the first iteration of the test App
+
+	var calls int
+	queueCall(func() { calls++ })
+	b.bind(1)
+	b.unbind()
+	if calls != 0 {
+		t.Fatal("flushed without a request")
+	}
+
+	// the event loop requests a flush that runs when a resource is bound
+	requestFlush()
+	b.bind(1)
+	b.unbind()
+	b.bind(1)
+	b.unbind()
+	if calls != 1 {
+		t.Fatal(calls)
+	}
+}
+
+func TestAppDo(t *testing.T) {
+	var a app
+	done := make(chan *Texture)
+	go func() {
+		var tex *Texture
+		<-a.Do(func() {
+			tex = NewTexture(image.Point{1, 1}, FilterNearest, ColorFormatRGBA, nil)
+		})
+		done <- tex
+	}()
+
+	// the calls run when the frame ends on the goroutine that owns the context
+	for {
+		flushCalls()
+		select {
+		case tex := <-done:
+			if tex.Size() != (image.Point{1, 1}) {
+				t.Fatal(tex.Size())
+			}
+			tex.Delete()
+			return
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
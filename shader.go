@@ -26,8 +26,23 @@ func (prg *ShaderProgram) End() {
 	shaderBinder.unbind()
 }
 
+// Delete deletes the ShaderProgram. It is safe to call Delete more than once.
+func (prg *ShaderProgram) Delete() {
+	if prg.id != 0 {
+		runtime.SetFinalizer(prg, nil)
+		prg.delete()
+	}
+}
+
 func (prg *ShaderProgram) delete() {
+	shaderBinder.forget(uint32(prg.id))
+	untrackObject("program", uint32(prg.id))
 	gl.DeleteProgram(prg.id)
+	prg.id = 0
+}
+
+func (prg *ShaderProgram) finalize() {
+	queueCall(prg.delete)
 }
 
 func (prg *ShaderProgram) getUniformLocation(name string) gl.Uniform {
@@ -113,7 +128,8 @@ func NewShaderProgram(vshader, fshader string) (*ShaderProgram, error) {
 			id:    gl.CreateProgram(),
 			attrs: map[string]gl.Uniform{},
 		}
-		runtime.SetFinalizer(prog, (*ShaderProgram).delete)
+		trackObject("program", uint32(prog.id))
+		runtime.SetFinalizer(prog, (*ShaderProgram).finalize)
 
 		gl.AttachShader(prog.id, vref)
 		gl.AttachShader(prog.id, fref)
@@ -0,0 +1,42 @@
+package pancake
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/askeladdk/pancake/mathx"
+	gl "github.com/askeladdk/pancake/opengl"
+)
+
+func TestShaderProgramVectorArrays(t *testing.T) {
+	prg, err := NewShaderProgram(`
+#version 330 core
+layout(location = 0) in vec2 in_Position;
+void main() { gl_Position = vec4(in_Position, 0, 1); }
+`, `
+#version 330 core
+uniform vec2 u_V[4];
+out vec4 out_FragColor;
+void main() { out_FragColor = vec4(u_V[0] + u_V[1] + u_V[2] + u_V[3], 0, 1); }
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer prg.Delete()
+
+	prg.Begin()
+	defer prg.End()
+
+	prg.SetUniform("u_V", []mathx.Vec2{{9, 9}, {9, 9}, {9, 9}, {9, 9}})
+	prg.SetUniform("u_V", []mathx.Vec2{{1, 2}, {3, 4}})
+	prg.SetUniform("u_V", []mathx.Vec2{})
+
+	for i, want := range []mathx.Vec2{{1, 2}, {3, 4}, {9, 9}, {9, 9}} {
+		loc := gl.GetUniformLocation(prg.id, fmt.Sprintf("u_V[%d]", i))
+		got := make([]float64, 2)
+		gl.GetUniformfv(prg.id, loc, got)
+		if got[0] != want[0] || got[1] != want[1] {
+			t.Fatal(i, got)
+		}
+	}
+}
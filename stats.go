@@ -0,0 +1,131 @@
+package pancake
+
+import (
+	"time"
+)
+
+// FrameTimeWindow is the number of frames over which frame times are tracked.
+const FrameTimeWindow = 120
+
+// FrameStats reports frame timing statistics.
+type FrameStats struct {
+	// DrawRate is the number of frames drawn per second.
+	DrawRate int
+
+	// UpdateRate is the number of FrameEvents per second.
+	UpdateRate int
+
+	// CPUTime is the time spent between Begin and End of the last frame.
+	CPUTime time.Duration
+
+	// FrameTime is the time between the last two frames.
+	FrameTime time.Duration
+
+	// MinFrameTime, AvgFrameTime and MaxFrameTime are computed
+	// over the last FrameTimeWindow frames.
+	MinFrameTime, AvgFrameTime, MaxFrameTime time.Duration
+
+	// DrawCalls is the number of draw calls issued in the last frame,
+	// not counting the draw of the upscale pass.
+	DrawCalls int
+
+	// Vertices is the number of vertices drawn in the last frame.
+	Vertices int
+}
+
+// drawCounter counts the draw calls and vertices of the current frame.
+var drawCounter struct {
+	calls, vertices int
+}
+
+func countDraw(vertices int) {
+	drawCounter.calls++
+	drawCounter.vertices += vertices
+}
+
+// frameTimer measures frame times between Begin and End pairs.
+type frameTimer struct {
+	begin     time.Time
+	end       time.Time
+	cpuTime   time.Duration
+	times     [FrameTimeWindow]time.Duration
+	pos, n    int
+	drawCount int
+	drawTime  time.Time
+	drawRate  int
+	drawCalls int
+	vertices  int
+}
+
+func (ft *frameTimer) beginFrame() {
+	ft.begin = time.Now()
+	drawCounter.calls, drawCounter.vertices = 0, 0
+}
+
+func (ft *frameTimer) endFrame() {
+	t := time.Now()
+	ft.cpuTime = t.Sub(ft.begin)
+	ft.drawCalls, ft.vertices = drawCounter.calls, drawCounter.vertices
+
+	if !ft.end.IsZero() {
+		ft.times[ft.pos] = t.Sub(ft.end)
+		ft.pos = (ft.pos + 1) % len(ft.times)
+		if ft.n < len(ft.times) {
+			ft.n++
+		}
+	}
+	ft.end = t
+
+	ft.drawCount++
+	if ft.drawTime.IsZero() {
+		ft.drawTime = t
+	} else if t.Sub(ft.drawTime).Seconds() >= 1 {
+		ft.drawRate, ft.drawCount, ft.drawTime = ft.drawCount, 0, t
+	}
+}
+
+// frameTimes appends the tracked frame times from oldest to newest.
+func (ft *frameTimer) frameTimes(dst []time.Duration) []time.Duration {
+	start := ft.pos - ft.n
+	if start < 0 {
+		start += len(ft.times)
+	}
+	for i := 0; i < ft.n; i++ {
+		dst = append(dst, ft.times[(start+i)%len(ft.times)])
+	}
+	return dst
+}
+
+func (ft *frameTimer) stats() FrameStats {
+	stats := FrameStats{
+		DrawRate:  ft.drawRate,
+		CPUTime:   ft.cpuTime,
+		DrawCalls: ft.drawCalls,
+		Vertices:  ft.vertices,
+	}
+
+	if ft.n == 0 {
+		return stats
+	}
+
+	last := ft.pos - 1
+	if last < 0 {
+		last += len(ft.times)
+	}
+	stats.FrameTime = ft.times[last]
+
+	var sum time.Duration
+	stats.MinFrameTime = ft.times[last]
+	for _, d := range ft.frameTimes(nil) {
+		sum += d
+		if d < stats.MinFrameTime {
+			stats.MinFrameTime = d
+		}
+		if d > stats.MaxFrameTime {
+			stats.MaxFrameTime = d
+		}
+	}
+	stats.AvgFrameTime = sum / time.Duration(ft.n)
+
+	return stats
+}
@@ -0,0 +1,43 @@
+package pancake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFrameTimer(t *testing.T) {
+	var ft frameTimer
+
+	for i := 1; i <= FrameTimeWindow+2; i++ {
+		ft.times[ft.pos] = time.Duration(i) * time.Millisecond
+		ft.pos = (ft.pos + 1) % len(ft.times)
+		if ft.n < len(ft.times) {
+			ft.n++
+		}
+	}
+
+	times := ft.frameTimes(nil)
+	if len(times) != FrameTimeWindow {
+		t.Fatal(len(times))
+	} else if times[0] != 3*time.Millisecond || times[len(times)-1] != (FrameTimeWindow+2)*time.Millisecond {
+		t.Fatal(times[0], times[len(times)-1])
+	}
+
+	stats := ft.stats()
+	if stats.FrameTime != (FrameTimeWindow+2)*time.Millisecond {
+		t.Fatal(stats.FrameTime)
+	} else if stats.MinFrameTime != 3*time.Millisecond {
+		t.Fatal(stats.MinFrameTime)
+	} else if stats.MaxFrameTime != (FrameTimeWindow+2)*time.Millisecond {
+		t.Fatal(stats.MaxFrameTime)
+	} else if stats.AvgFrameTime != (FrameTimeWindow+5)*time.Millisecond/2 {
+		t.Fatal(stats.AvgFrameTime)
+	}
+
+	countDraw(6)
+	countDraw(12)
+	ft.endFrame()
+	if stats := ft.stats(); stats.DrawCalls != 2 || stats.Vertices != 18 {
+		t.Fatal(stats.DrawCalls, stats.Vertices)
+	}
+}
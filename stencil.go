@@ -0,0 +1,119 @@
+package pancake
+
+import (
+	"errors"
+
+	gl "github.com/askeladdk/pancake/opengl"
+)
+
+// StencilMaskDepth is the maximum number of nested stencil masks.
+const StencilMaskDepth = 8
+
+// StencilMask clips all draw calls to an arbitrary shape that is
+// written to the stencil buffer. The framebuffer must have a stencil buffer
+// that has been cleared to zero.
+// Stencil masks are internally placed in a stack like Scissor. Begin() and End() pairs must
+// always be balanced for the stack to work properly. Nested masks clip to the
+// intersection of all masks on the stack, up to StencilMaskDepth levels deep.
+//
+// Every fragment that Mask rasterises is part of the mask regardless of its color,
+// so use a shader that discards transparent fragments to mask with sprites.
+//
+//	mask := pancake.StencilMask{Mask: func() { drawer.Draw(shape) }}
+//	mask.Begin()
+//	... (anything outside the shape is clipped)
+//	mask.End()
+type StencilMask struct {
+	// Mask draws the shape of the mask.
+	Mask func()
+	// Outside clips everything inside the shape instead of outside.
+	Outside bool
+}
+
+// stencilStack assigns one bit of the stencil buffer to each nesting level.
+type stencilStack struct {
+	stack []StencilMask
+}
+
+var stencilstack = &stencilStack{}
+
+// Begin writes the mask and applies it.
+func (m StencilMask) Begin() {
+	stencilstack.push(m)
+}
+
+// End erases the mask and re-applies the previous mask.
+func (m StencilMask) End() {
+	stencilstack.pop()
+}
+
+// ref returns the stencil value of the fragments that pass the masks on the stack.
+func (ss *stencilStack) ref() int {
+	ref := 0
+	for i, m := range ss.stack {
+		if !m.Outside {
+			ref |= 1 << i
+		}
+	}
+	return ref
+}
+
+// apply restricts drawing to the fragments that pass all masks on the stack.
+func (ss *stencilStack) apply() {
+	gl.StencilFunc(gl.EQUAL, ss.ref(), 1<<len(ss.stack)-1)
+	gl.StencilOp(gl.KEEP, gl.KEEP, gl.KEEP)
+}
+
+// saveWriteMasks returns a function that restores the current color and stencil write masks.
+func saveWriteMasks() func() {
+	color := make([]bool, 4)
+	gl.GetBooleanv(gl.COLOR_WRITEMASK, color)
+	stencil := uint32(gl.GetInteger(gl.STENCIL_WRITEMASK))
+	return func() {
+		gl.ColorMask(color[0], color[1], color[2], color[3])
+		gl.StencilMask(stencil)
+	}
+}
+
+func (ss *stencilStack) push(next StencilMask) {
+	level := len(ss.stack)
+	if level == StencilMaskDepth {
+		panic(errors.New("stencil masks nested too deep"))
+	} else if level == 0 {
+		gl.Enable(gl.STENCIL_TEST)
+	}
+
+	// set the bit of this level where the enclosing masks pass
+	restore := saveWriteMasks()
+	DepthNone.Begin()
+	gl.ColorMask(false, false, false, false)
+	gl.StencilMask(1 << level)
+	gl.StencilFunc(gl.EQUAL, ss.ref()|1<<level, 1<<level-1)
+	gl.StencilOp(gl.KEEP, gl.KEEP, gl.REPLACE)
+	next.Mask()
+	DepthNone.End()
+	restore()
+
+	ss.stack = append(ss.stack, next)
+	ss.apply()
+}
+
+func (ss *stencilStack) pop() {
+	level := len(ss.stack) - 1
+	ss.stack = ss.stack[:level]
+
+	// clear the bit of this level everywhere
+	restore := saveWriteMasks()
+	ZeroScissor.Begin()
+	gl.StencilMask(1 << level)
+	gl.ClearStencil(0)
+	gl.Clear(gl.STENCIL_BUFFER_BIT)
+	ZeroScissor.End()
+	restore()
+
+	if level == 0 {
+		gl.Disable(gl.STENCIL_TEST)
+	} else {
+		ss.apply()
+	}
+}
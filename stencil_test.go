@@ -0,0 +1,135 @@
+package pancake
+
+import (
+	"image"
+	"testing"
+
+	gl "github.com/askeladdk/pancake/opengl"
+)
+
+func TestStencilMaskNested(t *testing.T) {
+	fbo, err := NewFramebuffer(image.Point{8, 8}, FilterNearest, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fbo.Delete()
+
+	shader, err := NewShaderProgram(`
+#version 330 core
+layout(location = 0) in vec2 in_Position;
+void main() { gl_Position = vec4(in_Position, 0, 1); }
+`, `
+#version 330 core
+out vec4 out_FragColor;
+void main() { out_FragColor = vec4(1); }
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shader.Delete()
+
+	vbo := NewVertexBuffer(upscaleQuadFormat, 4, upscaleQuadVertices)
+	defer vbo.Delete()
+	quad := NewVertexArraySlice(vbo)
+	defer quad.Delete()
+
+	fbo.Begin()
+	defer fbo.End()
+	gl.Viewport(fbo.Bounds())
+	gl.ClearColor(0, 0, 0, 0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT | gl.STENCIL_BUFFER_BIT)
+	shader.Begin()
+	defer shader.End()
+	quad.Begin()
+	defer quad.End()
+
+	// masks fill a rectangle in GL coordinates, whose origin is at the bottom left
+	region := func(r image.Rectangle, outside bool) StencilMask {
+		return StencilMask{
+			Mask: func() {
+				Scissor(r).Begin()
+				quad.Draw(gl.TRIANGLE_STRIP)
+				Scissor(r).End()
+			},
+			Outside: outside,
+		}
+	}
+	left := region(image.Rect(0, 0, 4, 8), false)
+	bottom := region(image.Rect(0, 0, 8, 4), false)
+	notLeft := region(image.Rect(0, 0, 4, 8), true)
+	notBottom := region(image.Rect(0, 0, 8, 4), true)
+	nothing := StencilMask{Mask: func() {}, Outside: true}
+
+	// the expected rectangles are in image coordinates, whose origin is at the top left
+	for _, tc := range []struct {
+		name  string
+		masks []StencilMask
+		want  image.Rectangle
+	}{
+		{"left", []StencilMask{left}, image.Rect(0, 0, 4, 8)},
+		{"left and bottom", []StencilMask{left, bottom}, image.Rect(0, 4, 4, 8)},
+		{"bits cleared", []StencilMask{left, nothing}, image.Rect(0, 0, 4, 8)},
+		{"outside in inside", []StencilMask{left, notBottom}, image.Rect(0, 0, 4, 4)},
+		{"inside in outside", []StencilMask{notLeft, bottom}, image.Rect(4, 4, 8, 8)},
+		{"outside in outside", []StencilMask{notLeft, notBottom}, image.Rect(4, 0, 8, 4)},
+		{"bits cleared again", []StencilMask{nothing, nothing}, image.Rect(0, 0, 8, 8)},
+	} {
+		gl.Clear(gl.COLOR_BUFFER_BIT)
+		for _, m := range tc.masks {
+			m.Begin()
+		}
+		quad.Draw(gl.TRIANGLE_STRIP)
+		for i := len(tc.masks) - 1; i >= 0; i-- {
+			tc.masks[i].End()
+		}
+
+		img := fbo.Image()
+		for y := 0; y < 8; y++ {
+			for x := 0; x < 8; x++ {
+				lit := img.RGBAAt(x, y).A == 255
+				if want := image.Pt(x, y).In(tc.want); lit != want {
+					t.Fatalf("%s: pixel (%d, %d) lit %v, want %v", tc.name, x, y, lit, want)
+				}
+			}
+		}
+	}
+}
+
+func TestStencilMaskWriteMasks(t *testing.T) {
+	gl.ColorMask(true, false, true, false)
+	gl.StencilMask(0x0f)
+	defer gl.ColorMask(true, true, true, true)
+	defer gl.StencilMask(0xff)
+
+	check := func(when string) {
+		color := make([]bool, 4)
+		gl.GetBooleanv(gl.COLOR_WRITEMASK, color)
+		if !color[0] || color[1] || !color[2] || color[3] {
+			t.Fatal(when, color)
+		} else if stencil := gl.GetInteger(gl.STENCIL_WRITEMASK); stencil != 0x0f {
+			t.Fatal(when, stencil)
+		}
+	}
+
+	mask := StencilMask{Mask: func() {}}
+	mask.Begin()
+	check("begin")
+	mask.End()
+	check("end")
+}
+
+func TestStencilMaskDepth(t *testing.T) {
+	mask := StencilMask{Mask: func() {}}
+	for i := 0; i < StencilMaskDepth; i++ {
+		mask.Begin()
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic")
+		}
+		for i := 0; i < StencilMaskDepth; i++ {
+			mask.End()
+		}
+	}()
+	mask.Begin()
+}
@@ -0,0 +1,104 @@
+package pancake
+
+import (
+	"errors"
+	"image"
+	"runtime"
+
+	gl "github.com/askeladdk/pancake/opengl"
+)
+
+// StreamingTexture is a Texture that is replaced every frame,
+// such as a video frame or a software rendered canvas.
+// Pixels are uploaded through two alternating pixel buffer objects so that
+// the transfer to the Texture does not stall the pipeline.
+type StreamingTexture struct {
+	*Texture
+	pbos [2]gl.Buffer
+	next int
+}
+
+// NewStreamingTexture creates a StreamingTexture.
+func NewStreamingTexture(size image.Point, format ColorFormat, opt TextureOptions) *StreamingTexture {
+	st := &StreamingTexture{
+		Texture: NewTextureWithOptions(size, format, nil, opt),
+	}
+
+	for i := range st.pbos {
+		st.pbos[i] = gl.CreateBuffer()
+		trackObject("buffer", uint32(st.pbos[i]))
+	}
+	runtime.SetFinalizer(st, (*StreamingTexture).finalize)
+
+	return st
+}
+
+// Upload replaces all pixels of the Texture.
+func (st *StreamingTexture) Upload(pixels []byte) {
+	if st.pbos[0] == 0 {
+		panic(errors.New("streaming texture has been deleted"))
+	} else if len(pixels) != st.len() {
+		panic(errors.New("wrong buffer size"))
+	}
+
+	pbo := st.pbos[st.next]
+	st.next = 1 - st.next
+
+	gl.BindBuffer(gl.PIXEL_UNPACK_BUFFER, pbo)
+	defer gl.BindBuffer(gl.PIXEL_UNPACK_BUFFER, 0)
+
+	// orphan the previous storage instead of waiting for its transfer to complete
+	gl.BufferData(gl.PIXEL_UNPACK_BUFFER, len(pixels), nil, gl.STREAM_DRAW)
+	gl.BufferSubData(gl.PIXEL_UNPACK_BUFFER, 0, len(pixels), gl.Ptr(pixels))
+
+	st.Begin()
+	defer st.End()
+	unpackAlignment(st.format, st.size.X)
+
+	// nil pixels read from offset 0 of the bound pixel buffer
+	gl.TexSubImage2D(
+		gl.TEXTURE_2D,
+		0,
+		0,
+		0,
+		st.size.X,
+		st.size.Y,
+		st.format.format(),
+		st.format.xtype(),
+		nil,
+	)
+	if st.mipmaps {
+		gl.GenerateMipmap(gl.TEXTURE_2D)
+	}
+}
+
+// UploadImage replaces all pixels of the Texture with an image of the same size.
+// The image is converted to the color format of the Texture like in SetImage.
+func (st *StreamingTexture) UploadImage(img image.Image) {
+	if img.Bounds().Size() != st.size {
+		panic(errors.New("image size does not match texture size"))
+	}
+	st.Upload(imagePixAs(img, st.format))
+}
+
+// Delete deletes the Texture and the pixel buffer objects.
+// It is safe to call Delete more than once.
+func (st *StreamingTexture) Delete() {
+	runtime.SetFinalizer(st, nil)
+	st.delete()
+	st.Texture.Delete()
+}
+
+func (st *StreamingTexture) delete() {
+	for i, pbo := range st.pbos {
+		if pbo != 0 {
+			untrackObject("buffer", uint32(pbo))
+			gl.DeleteBuffer(pbo)
+			st.pbos[i] = 0
+		}
+	}
+}
+
+func (st *StreamingTexture) finalize() {
+	queueCall(st.delete)
+}
@@ -3,8 +3,11 @@ package pancake
 import (
 	"errors"
 	"image"
+	"image/color"
 	"image/draw"
+	"reflect"
 	"runtime"
+	"unsafe"
 
 	"github.com/askeladdk/pancake/mathx"
 
@@ -28,10 +31,33 @@ var texbinders = func(n uint32) []*binder {
 	return binders
 }(TextureUnitsCount)
 
+// TextureOptions determines how a Texture is sampled.
+// The zero value repeats the texture and filters it linearly without mipmaps.
+type TextureOptions struct {
+	// MinFilter is the minifying filter.
+	// Mipmaps are generated automatically if it is a mipmap filter.
+	MinFilter TextureFilter
+	// MagFilter is the magnifying filter. It cannot be a mipmap filter.
+	MagFilter TextureFilter
+	// WrapS and WrapT are the wrap modes of the horizontal and vertical texture coordinates.
+	WrapS, WrapT TextureWrap
+	// BorderColor is the color sampled outside the texture with WrapClampToBorder.
+	// Defaults to transparent black.
+	BorderColor color.Color
+	// Anisotropy is the maximum degree of anisotropic filtering.
+	// It is clamped to the maximum supported by the GL and ignored if unsupported.
+	// Values of 1 or less disable anisotropic filtering.
+	Anisotropy float64
+	// Premultiply multiplies the color channels of NRGBA images by alpha
+	// when the Texture is created from an image.
+	Premultiply bool
+}
+
 type Texture struct {
-	size   image.Point
-	format ColorFormat
-	id     gl.Texture
+	size    image.Point
+	format  ColorFormat
+	id      gl.Texture
+	mipmaps bool
 }
 
 func (tex *Texture) BeginAt(unit int) {
@@ -58,12 +84,129 @@ func (tex *Texture) ColorFormat() ColorFormat {
 	return tex.format
 }
 
+// SetFilter changes the minifying and magnifying filters.
+// Mipmaps are generated if the minifying filter is a mipmap filter.
+func (tex *Texture) SetFilter(min, mag TextureFilter) {
+	if mag.mipmap() {
+		panic(errors.New("magnifying filter cannot be a mipmap filter"))
+	}
+
+	tex.Begin()
+	defer tex.End()
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, min.param())
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, mag.param())
+
+	if min.mipmap() && !tex.mipmaps {
+		gl.GenerateMipmap(gl.TEXTURE_2D)
+	}
+	tex.mipmaps = min.mipmap()
+}
+
+// SetWrap changes the wrap modes of the horizontal and vertical texture coordinates.
+func (tex *Texture) SetWrap(s, t TextureWrap) {
+	tex.Begin()
+	defer tex.End()
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, s.param())
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, t.param())
+}
+
+// SetBorderColor changes the color that is sampled outside the texture with WrapClampToBorder.
+func (tex *Texture) SetBorderColor(c color.Color) {
+	r, g, b, a := c.RGBA()
+	tex.Begin()
+	defer tex.End()
+	gl.TexParameterfv(gl.TEXTURE_2D, gl.TEXTURE_BORDER_COLOR, []float64{
+		float64(r) / 0xffff,
+		float64(g) / 0xffff,
+		float64(b) / 0xffff,
+		float64(a) / 0xffff,
+	})
+}
+
+// SetAnisotropy changes the maximum degree of anisotropic filtering.
+// It has no effect if anisotropic filtering is not supported.
+func (tex *Texture) SetAnisotropy(anisotropy float64) {
+	max := maxAnisotropy()
+	if max == 0 {
+		return
+	} else if anisotropy > max {
+		anisotropy = max
+	} else if anisotropy < 1 {
+		anisotropy = 1
+	}
+
+	tex.Begin()
+	defer tex.End()
+	gl.TexParameterf(gl.TEXTURE_2D, gl.TEXTURE_MAX_ANISOTROPY, anisotropy)
+}
+
+// SetOptions changes all sampling options.
+func (tex *Texture) SetOptions(opt TextureOptions) {
+	tex.SetWrap(opt.WrapS, opt.WrapT)
+	if opt.BorderColor != nil {
+		tex.SetBorderColor(opt.BorderColor)
+	}
+	if opt.Anisotropy > 1 {
+		tex.SetAnisotropy(opt.Anisotropy)
+	}
+	tex.SetFilter(opt.MinFilter, opt.MagFilter)
+}
+
+// GenerateMipmaps regenerates the mipmaps from the base level.
+// SetPixels regenerates the mipmaps automatically.
+func (tex *Texture) GenerateMipmaps() {
+	tex.Begin()
+	defer tex.End()
+	gl.GenerateMipmap(gl.TEXTURE_2D)
+}
+
+// pixelAlignment caches GL_PACK_ALIGNMENT and GL_UNPACK_ALIGNMENT.
+var pixelAlignment = struct {
+	pack, unpack int
+}{4, 4}
+
+// unpackAlignment sets GL_UNPACK_ALIGNMENT for uploading tightly packed rows.
+func unpackAlignment(format ColorFormat, width int) {
+	if align := format.rowAlignment(width); align != pixelAlignment.unpack {
+		gl.PixelStorei(gl.UNPACK_ALIGNMENT, align)
+		pixelAlignment.unpack = align
+	}
+}
+
+// packAlignment sets GL_PACK_ALIGNMENT for downloading tightly packed rows.
+func packAlignment(format ColorFormat, width int) {
+	if align := format.rowAlignment(width); align != pixelAlignment.pack {
+		gl.PixelStorei(gl.PACK_ALIGNMENT, align)
+		pixelAlignment.pack = align
+	}
+}
+
+// anisotropy is the maximum supported anisotropy, or zero if unsupported. It is queried once.
+var anisotropy = struct {
+	max     float64
+	queried bool
+}{}
+
+func maxAnisotropy() float64 {
+	if !anisotropy.queried {
+		if gl.ExtensionSupported("GL_EXT_texture_filter_anisotropic") ||
+			gl.ExtensionSupported("GL_ARB_texture_filter_anisotropic") {
+			anisotropy.max = gl.GetFloat(gl.MAX_TEXTURE_MAX_ANISOTROPY)
+		}
+		anisotropy.queried = true
+	}
+	return anisotropy.max
+}
+
 func (tex *Texture) SetPixels(pixels []byte) {
 	if tex.id == 0 {
 		panic(errors.New("screen texture cannot be accessed"))
 	} else if len(pixels) != tex.len() {
 		panic(errors.New("wrong buffer size"))
 	} else {
+		tex.Begin()
+		defer tex.End()
+		unpackAlignment(tex.format, tex.size.X)
 		gl.TexSubImage2D(
 			gl.TEXTURE_2D,
 			0,
@@ -72,13 +215,60 @@ func (tex *Texture) SetPixels(pixels []byte) {
 			tex.size.X,
 			tex.size.Y,
 			tex.format.format(),
-			gl.UNSIGNED_BYTE,
+			tex.format.xtype(),
 			pixels,
 		)
 		panicError()
+		if tex.mipmaps {
+			gl.GenerateMipmap(gl.TEXTURE_2D)
+		}
+	}
+}
+
+// SetPixelsRegion replaces the pixels in a rectangle of the Texture.
+// The rows of pixels are tightly packed.
+func (tex *Texture) SetPixelsRegion(r image.Rectangle, pixels []byte) {
+	if tex.id == 0 {
+		panic(errors.New("screen texture cannot be accessed"))
+	} else if !r.In(image.Rectangle{Max: tex.size}) {
+		panic(errors.New("region out of bounds"))
+	} else if len(pixels) != r.Dx()*r.Dy()*tex.format.pixelSize() {
+		panic(errors.New("wrong buffer size"))
+	} else if r.Empty() {
+		return
+	}
+
+	tex.Begin()
+	defer tex.End()
+	unpackAlignment(tex.format, r.Dx())
+	gl.TexSubImage2D(
+		gl.TEXTURE_2D,
+		0,
+		r.Min.X,
+		r.Min.Y,
+		r.Dx(),
+		r.Dy(),
+		tex.format.format(),
+		tex.format.xtype(),
+		pixels,
+	)
+	panicError()
+	if tex.mipmaps {
+		gl.GenerateMipmap(gl.TEXTURE_2D)
 	}
 }
 
+// SetImage copies an image into the Texture with its top left corner at the given point.
+// The image is converted to the color format of the Texture if needed.
+// Single channel formats store the luminance of the image and two channel formats
+// store its red and green channels.
+// SetImage panics if the Texture has a depth format or if it is
+// ColorFormatIndexed and the image is not Paletted.
+func (tex *Texture) SetImage(img image.Image, at image.Point) {
+	r := image.Rectangle{Min: at, Max: at.Add(img.Bounds().Size())}
+	tex.SetPixelsRegion(r, imagePixAs(img, tex.format))
+}
+
 func (tex *Texture) Pixels(pixels []byte) []byte {
 	if tex.id == 0 {
 		panic(errors.New("screen texture cannot be accessed"))
@@ -88,11 +278,14 @@ func (tex *Texture) Pixels(pixels []byte) []byte {
 		panic(errors.New("buffer too small"))
 	}
 
+	tex.Begin()
+	defer tex.End()
+	packAlignment(tex.format, tex.size.X)
 	gl.GetTexImage(
 		gl.TEXTURE_2D,
 		0,
 		tex.format.format(),
-		gl.UNSIGNED_BYTE,
+		tex.format.xtype(),
 		pixels,
 	)
 	panicError()
@@ -124,21 +317,49 @@ func (tex *Texture) len() int {
 	return tex.size.Y * tex.size.X * tex.ColorFormat().pixelSize()
 }
 
+// Delete deletes the Texture. It is safe to call Delete more than once.
+func (tex *Texture) Delete() {
+	if tex.id != 0 {
+		runtime.SetFinalizer(tex, nil)
+		tex.delete()
+	}
+}
+
 func (tex *Texture) delete() {
+	for _, b := range texbinders {
+		b.forget(uint32(tex.id))
+	}
+	untrackObject("texture", uint32(tex.id))
 	gl.DeleteTexture(tex.id)
+	tex.id = 0
+}
+
+func (tex *Texture) finalize() {
+	queueCall(tex.delete)
 }
 
+// NewTexture creates a Texture that uses filter for minifying and magnifying.
 func NewTexture(size image.Point, filter TextureFilter, format ColorFormat, pixels []byte) *Texture {
+	return NewTextureWithOptions(size, format, pixels, TextureOptions{
+		MinFilter: filter,
+		MagFilter: filter,
+	})
+}
+
+// NewTextureWithOptions creates a Texture with the given sampling options.
+func NewTextureWithOptions(size image.Point, format ColorFormat, pixels []byte, opt TextureOptions) *Texture {
 	tex := &Texture{
 		id:     gl.CreateTexture(),
 		size:   size,
 		format: format,
 	}
-	runtime.SetFinalizer(tex, (*Texture).delete)
+	trackObject("texture", uint32(tex.id))
+	runtime.SetFinalizer(tex, (*Texture).finalize)
 
 	tex.Begin()
 	defer tex.End()
 
+	unpackAlignment(format, size.X)
 	gl.TexImage2D(
 		gl.TEXTURE_2D,
 		0,
@@ -146,32 +367,191 @@ func NewTexture(size image.Point, filter TextureFilter, format ColorFormat, pixe
 		size.X,
 		size.Y,
 		format.format(),
-		gl.UNSIGNED_BYTE,
+		format.xtype(),
 		pixels,
 	)
 
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, filter.param())
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, filter.param())
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.REPEAT)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.REPEAT)
-
+	tex.SetOptions(opt)
 	return tex
 }
 
-func imagePix(img image.Image) ([]uint8, ColorFormat) {
+// packRows returns h rows of rowSize bytes from pix without the row padding.
+func packRows(pix []byte, stride, rowSize, h int) []byte {
+	if stride == rowSize {
+		return pix[:rowSize*h]
+	}
+	packed := make([]byte, 0, rowSize*h)
+	for y := 0; y < h; y++ {
+		packed = append(packed, pix[y*stride:y*stride+rowSize]...)
+	}
+	return packed
+}
+
+// premultiplyNRGBA returns the pixels of an NRGBA image as premultiplied RGBA.
+func premultiplyNRGBA(im *image.NRGBA) []byte {
+	b := im.Bounds()
+	pix := make([]byte, 0, 4*b.Dx()*b.Dy())
+	for y := 0; y < b.Dy(); y++ {
+		i := im.PixOffset(b.Min.X, b.Min.Y+y)
+		pix = append(pix, im.Pix[i:i+4*b.Dx()]...)
+		row := pix[len(pix)-4*b.Dx():]
+		for x := 0; x < len(row); x += 4 {
+			a := uint32(row[x+3])
+			row[x+0] = uint8(uint32(row[x+0]) * a / 255)
+			row[x+1] = uint8(uint32(row[x+1]) * a / 255)
+			row[x+2] = uint8(uint32(row[x+2]) * a / 255)
+		}
+	}
+	return pix
+}
+
+// imagePix returns the tightly packed pixels of an image and the matching color format.
+// NRGBA images are premultiplied if premultiply is set.
+// Other images are converted to premultiplied RGBA.
+func imagePix(img image.Image, premultiply bool) ([]byte, ColorFormat) {
+	b := img.Bounds()
 	switch im := img.(type) {
 	case *image.RGBA:
-		return im.Pix, ColorFormatRGBA
+		return imagePixAs(img, ColorFormatRGBA), ColorFormatRGBA
+	case *image.NRGBA:
+		if premultiply {
+			return premultiplyNRGBA(im), ColorFormatRGBA
+		}
+		return packRows(im.Pix[im.PixOffset(b.Min.X, b.Min.Y):], im.Stride, 4*b.Dx(), b.Dy()), ColorFormatRGBA
+	case *image.Gray, *image.Alpha:
+		return imagePixAs(img, ColorFormatR8), ColorFormatR8
 	case *image.Paletted:
-		return im.Pix, ColorFormatIndexed
+		return imagePixAs(img, ColorFormatIndexed), ColorFormatIndexed
 	default:
-		rgba := image.NewRGBA(img.Bounds())
-		draw.Draw(rgba, rgba.Bounds(), img, image.Point{}, draw.Src)
-		return rgba.Pix, ColorFormatRGBA
+		return imagePixAs(img, ColorFormatRGBA), ColorFormatRGBA
+	}
+}
+
+// imagePixAs returns the tightly packed pixels of an image in the given color format.
+// Single channel formats store the luminance of the image and two channel formats
+// store its red and green channels. Only Paletted images can be converted to
+// ColorFormatIndexed and no image can be converted to a depth format.
+func imagePixAs(img image.Image, format ColorFormat) []byte {
+	b := img.Bounds()
+	switch format {
+	case ColorFormatRGBA:
+		if im, ok := img.(*image.RGBA); ok {
+			return packRows(im.Pix[im.PixOffset(b.Min.X, b.Min.Y):], im.Stride, 4*b.Dx(), b.Dy())
+		}
+		rgba := image.NewRGBA(image.Rectangle{Max: b.Size()})
+		draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+		return rgba.Pix
+	case ColorFormatRGB, ColorFormatRG8:
+		return selectChannels(imagePixAs(img, ColorFormatRGBA), 4, format.channels())
+	case ColorFormatR8:
+		switch im := img.(type) {
+		case *image.Gray:
+			return packRows(im.Pix[im.PixOffset(b.Min.X, b.Min.Y):], im.Stride, b.Dx(), b.Dy())
+		case *image.Alpha:
+			return packRows(im.Pix[im.PixOffset(b.Min.X, b.Min.Y):], im.Stride, b.Dx(), b.Dy())
+		}
+		gray := image.NewGray(image.Rectangle{Max: b.Size()})
+		draw.Draw(gray, gray.Bounds(), img, b.Min, draw.Src)
+		return gray.Pix
+	case ColorFormatR16, ColorFormatR16F, ColorFormatR32F:
+		gray := image.NewGray16(image.Rectangle{Max: b.Size()})
+		draw.Draw(gray, gray.Bounds(), img, b.Min, draw.Src)
+		return convertPix16(gray.Pix, 1, format)
+	case ColorFormatRGBA16, ColorFormatRGBA16F, ColorFormatRGBA32F,
+		ColorFormatRG16F, ColorFormatRG32F:
+		rgba := image.NewRGBA64(image.Rectangle{Max: b.Size()})
+		draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+		return convertPix16(rgba.Pix, 4, format)
+	case ColorFormatIndexed:
+		if im, ok := img.(*image.Paletted); ok {
+			return packRows(im.Pix[im.PixOffset(b.Min.X, b.Min.Y):], im.Stride, b.Dx(), b.Dy())
+		}
+	}
+	panic(errors.New("image cannot be converted to the color format of the texture"))
+}
+
+// selectChannels keeps the first n of every stride bytes.
+func selectChannels(pix []byte, stride, n int) []byte {
+	dst := make([]byte, 0, len(pix)/stride*n)
+	for i := 0; i < len(pix); i += stride {
+		dst = append(dst, pix[i:i+n]...)
+	}
+	return dst
+}
+
+// convertPix16 converts big endian 16-bit pixels of the given number of channels
+// to the native endian uint16 or float32 pixels of a color format.
+func convertPix16(pix []byte, channels int, format ColorFormat) []byte {
+	n := format.channels()
+	count := len(pix) / (2 * channels)
+	value := func(i, c int) uint16 {
+		j := 2 * (i*channels + c)
+		return uint16(pix[j])<<8 | uint16(pix[j+1])
+	}
+
+	if format.xtype() == gl.UNSIGNED_SHORT {
+		dst := make([]uint16, count*n)
+		for i := 0; i < count; i++ {
+			for c := 0; c < n; c++ {
+				dst[i*n+c] = value(i, c)
+			}
+		}
+		return sliceBytes(unsafe.Pointer(&dst), 2)
+	}
+
+	dst := make([]float32, count*n)
+	for i := 0; i < count; i++ {
+		for c := 0; c < n; c++ {
+			dst[i*n+c] = float32(value(i, c)) / 0xffff
+		}
 	}
+	return sliceBytes(unsafe.Pointer(&dst), 4)
+}
+
+// sliceBytes reinterprets a pointer to a slice of elements of the given size as bytes.
+func sliceBytes(slice unsafe.Pointer, size int) []byte {
+	src := (*reflect.SliceHeader)(slice)
+	var b []byte
+	dst := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	dst.Data = src.Data
+	dst.Len = src.Len * size
+	dst.Cap = src.Cap * size
+	return b
 }
 
+// NewTextureFromImage creates a Texture from an image.
+// RGBA, NRGBA, Gray, Alpha and Paletted images are uploaded without conversion.
+// NRGBA images are premultiplied.
+// Gray and Alpha images are stored as ColorFormatR8 but sampled like their
+// RGBA conversion: gray as opaque (g, g, g, 1) and alpha as premultiplied white (a, a, a, a).
+// Paletted images become ColorFormatIndexed textures whose colors are in a separate Palette.
 func NewTextureFromImage(img image.Image, filter TextureFilter) *Texture {
-	pix, format := imagePix(img)
-	return NewTexture(img.Bounds().Size(), filter, format, pix)
+	return NewTextureFromImageWithOptions(img, TextureOptions{
+		MinFilter:   filter,
+		MagFilter:   filter,
+		Premultiply: true,
+	})
+}
+
+// NewTextureFromImageWithOptions creates a Texture from an image with the given sampling options.
+func NewTextureFromImageWithOptions(img image.Image, opt TextureOptions) *Texture {
+	pix, format := imagePix(img, opt.Premultiply)
+	tex := NewTextureWithOptions(img.Bounds().Size(), format, pix, opt)
+
+	// sample single channel images the same as their premultiplied RGBA conversion
+	switch img.(type) {
+	case *image.Gray:
+		tex.setSwizzle(gl.RED, gl.RED, gl.RED, gl.ONE)
+	case *image.Alpha:
+		tex.setSwizzle(gl.RED, gl.RED, gl.RED, gl.RED)
+	}
+
+	return tex
+}
+
+// setSwizzle selects the channels that the red, green, blue and alpha channels are sampled from.
+func (tex *Texture) setSwizzle(r, g, b, a gl.Enum) {
+	tex.Begin()
+	defer tex.End()
+	gl.TexParameteriv(gl.TEXTURE_2D, gl.TEXTURE_SWIZZLE_RGBA, []int{int(r), int(g), int(b), int(a)})
 }
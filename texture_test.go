@@ -0,0 +1,161 @@
+package pancake
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+	"unsafe"
+
+	gl "github.com/askeladdk/pancake/opengl"
+)
+
+func TestTextureOptions(t *testing.T) {
+	pixels := make([]byte, 4*4*4)
+	for i := range pixels {
+		pixels[i] = byte(i)
+	}
+
+	tex := NewTextureWithOptions(image.Point{4, 4}, ColorFormatRGBA, pixels, TextureOptions{
+		MinFilter:   FilterLinearMipmapLinear,
+		MagFilter:   FilterNearest,
+		WrapS:       WrapClampToBorder,
+		WrapT:       WrapMirroredRepeat,
+		BorderColor: color.White,
+		Anisotropy:  16,
+	})
+	defer tex.Delete()
+
+	if !tex.mipmaps {
+		t.Fatal("mipmaps")
+	} else if code := gl.GetError(); code != gl.NO_ERROR {
+		t.Fatal(gl.ErrorString(code))
+	}
+
+	tex.SetPixels(pixels)
+	if !bytes.Equal(tex.Pixels(nil), pixels) {
+		t.Fatal("pixels")
+	}
+
+	tex.SetFilter(FilterNearest, FilterNearest)
+	if tex.mipmaps {
+		t.Fatal("mipmaps")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic")
+		}
+	}()
+	tex.SetFilter(FilterLinear, FilterLinearMipmapLinear)
+}
+
+func TestTextureRegion(t *testing.T) {
+	tex := NewTexture(image.Point{4, 4}, FilterNearest, ColorFormatRGBA, make([]byte, 4*4*4))
+	defer tex.Delete()
+
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+	tex.SetImage(img, image.Point{2, 2})
+
+	pixels := tex.Pixels(nil)
+	if pixels[(1*4+1)*4] != 0 || pixels[(2*4+2)*4] != 255 || pixels[(3*4+3)*4+3] != 255 {
+		t.Fatal(pixels)
+	}
+
+	tex.SetPixelsRegion(image.Rect(0, 0, 1, 1), []byte{7, 7, 7, 7})
+	if pixels = tex.Pixels(pixels); pixels[0] != 7 || pixels[4] != 0 {
+		t.Fatal(pixels)
+	}
+}
+
+func TestStreamingTexture(t *testing.T) {
+	st := NewStreamingTexture(image.Point{2, 2}, ColorFormatRGBA, TextureOptions{})
+	defer st.Delete()
+
+	for frame := byte(1); frame <= 3; frame++ {
+		st.Upload(bytes.Repeat([]byte{frame}, 2*2*4))
+		if pixels := st.Pixels(nil); pixels[0] != frame || pixels[15] != frame {
+			t.Fatal(frame, pixels)
+		}
+	}
+}
+
+func TestTextureFormats(t *testing.T) {
+	// rows of 3x3 RGB pixels are not 4-byte aligned
+	rgb := make([]byte, 3*3*3)
+	for i := range rgb {
+		rgb[i] = byte(i)
+	}
+	tex := NewTexture(image.Point{3, 3}, FilterNearest, ColorFormatRGB, rgb)
+	defer tex.Delete()
+	if !bytes.Equal(tex.Pixels(nil), rgb) {
+		t.Fatal("rgb")
+	}
+
+	gray := image.NewGray(image.Rect(0, 0, 5, 5))
+	for i := range gray.Pix {
+		gray.Pix[i] = byte(i)
+	}
+	sub := gray.SubImage(image.Rect(1, 1, 4, 4)).(*image.Gray)
+	gtex := NewTextureFromImage(sub, FilterNearest)
+	defer gtex.Delete()
+	if gtex.format != ColorFormatR8 {
+		t.Fatal("gray format")
+	} else if !bytes.Equal(gtex.Pixels(nil), []byte{6, 7, 8, 11, 12, 13, 16, 17, 18}) {
+		t.Fatal("gray pixels")
+	}
+
+	nrgba := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	copy(nrgba.Pix, []byte{255, 128, 0, 128})
+	for _, tc := range []struct {
+		premultiply bool
+		want        []byte
+	}{
+		{false, []byte{255, 128, 0, 128}},
+		{true, []byte{128, 64, 0, 128}},
+	} {
+		ntex := NewTextureFromImageWithOptions(nrgba, TextureOptions{Premultiply: tc.premultiply})
+		if got := ntex.Pixels(nil); !bytes.Equal(got, tc.want) {
+			t.Fatal(tc.premultiply, got)
+		}
+		ntex.Delete()
+	}
+}
+
+func TestTextureSetImageFormats(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	copy(img.Pix, []byte{255, 128, 0, 255, 0, 64, 255, 255})
+
+	gray := func(x int) uint16 {
+		return color.Gray16Model.Convert(img.At(x, 0)).(color.Gray16).Y
+	}
+	r16 := []uint16{gray(0), gray(1)}
+	rgba32f := []float32{1, 128.0 / 255, 0, 1, 0, 64.0 / 255, 1, 1}
+
+	for _, tc := range []struct {
+		format ColorFormat
+		want   []byte
+	}{
+		{ColorFormatRGB, []byte{255, 128, 0, 0, 64, 255}},
+		{ColorFormatRG8, []byte{255, 128, 0, 64}},
+		{ColorFormatR16, sliceBytes(unsafe.Pointer(&r16), 2)},
+		{ColorFormatRGBA32F, sliceBytes(unsafe.Pointer(&rgba32f), 4)},
+	} {
+		tex := NewTexture(image.Point{2, 1}, FilterNearest, tc.format, nil)
+		tex.SetImage(img, image.Point{})
+		if got := tex.Pixels(nil); !bytes.Equal(got, tc.want) {
+			t.Errorf("format %d: got %v, want %v", tc.format, got, tc.want)
+		}
+		tex.Delete()
+	}
+
+	st := NewStreamingTexture(image.Point{2, 1}, ColorFormatRG8, TextureOptions{})
+	defer st.Delete()
+	st.UploadImage(img)
+	if got := st.Pixels(nil); !bytes.Equal(got, []byte{255, 128, 0, 64}) {
+		t.Fatal(got)
+	}
+}
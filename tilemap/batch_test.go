@@ -0,0 +1,104 @@
+package tilemap
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"os"
+	"testing"
+
+	"github.com/askeladdk/pancake"
+	"github.com/askeladdk/pancake/mathx"
+	"github.com/askeladdk/pancake/pancake2d"
+	"github.com/askeladdk/pancake/pancaketest"
+)
+
+func init() {
+	flag.BoolVar(&pancaketest.Update, "update", false, "update golden files")
+}
+
+func TestMain(m *testing.M) {
+	pancake.Main(pancake.Options{
+		WindowSize: image.Point{320, 200},
+		Headless:   true,
+	}, func(_ pancake.App) error {
+		os.Exit(m.Run())
+		return nil
+	})
+}
+
+// testTileSet has a red tile and a blue tile of 4x4 pixels.
+type testTileSet struct {
+	texture *pancake.Texture
+}
+
+func (ts *testTileSet) Texture() *pancake.Texture { return ts.texture }
+
+func (ts *testTileSet) TileRegion(id TileID) pancake.TextureRegion {
+	return pancake.NewTextureRegion(ts.texture.Size(), image.Rect(4*int(id), 0, 4*int(id)+4, 4))
+}
+
+func (ts *testTileSet) SameBaseTile(id0, id1 TileID) bool { return id0 == id1 }
+
+func (ts *testTileSet) IsAutoTile(TileID) (TileID, AutoTiler) { return Absent, nil }
+
+func (ts *testTileSet) TileSize() mathx.Vec2 { return mathx.Vec2{4, 4} }
+
+// testTileMap is a 5x5 checkerboard with a hole at (1, 1).
+type testTileMap struct {
+	tileSet TileSet
+}
+
+func (tm *testTileMap) TileAt(x, y int) TileID {
+	if x < 0 || y < 0 || x >= 5 || y >= 5 || (x == 1 && y == 1) {
+		return Absent
+	}
+	return TileID((x + y) % 2)
+}
+
+func (tm *testTileMap) SetTileAt(x, y int, id TileID) {}
+
+func (tm *testTileMap) TintColorAt(x, y int) color.Color { return color.RGBA{255, 255, 255, 255} }
+
+func (tm *testTileMap) TileSet() TileSet { return tm.tileSet }
+
+func TestBatchGolden(t *testing.T) {
+	pixels := make([]byte, 0, 8*4*4)
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 8; x++ {
+			if x < 4 {
+				pixels = append(pixels, 255, 0, 0, 255)
+			} else {
+				pixels = append(pixels, 0, 0, 255, 255)
+			}
+		}
+	}
+	tex := pancake.NewTexture(image.Point{8, 4}, pancake.FilterNearest, pancake.ColorFormatRGBA, pixels)
+	defer tex.Delete()
+
+	// the camera is offset by half a tile so that the edge tiles are partially visible
+	camera := &pancake2d.Camera{
+		Viewport: mathx.Rectangle{Max: mathx.Vec2{16, 16}},
+		Pos:      mathx.Vec2{2, 2},
+	}
+
+	var batch Batch
+	batch.Update(&testTileMap{tileSet: &testTileSet{texture: tex}}, camera)
+
+	drawer := pancake2d.NewSpriteDrawer(32)
+	defer drawer.Delete()
+
+	img, err := pancaketest.RenderFrame(image.Point{16, 16}, func() {
+		shader := pancake2d.DefaultShader()
+		shader.Begin()
+		defer shader.End()
+		shader.SetUniform("u_Projection", mathx.Ortho2D(0, 16, 16, 0))
+		shader.SetUniform("u_Texture", 0)
+		drawer.Draw(&batch)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pancaketest.AssertGolden(t, "batch", img, 2)
+}
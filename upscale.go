@@ -0,0 +1,271 @@
+package pancake
+
+import (
+	"image"
+
+	"github.com/askeladdk/pancake/mathx"
+	gl "github.com/askeladdk/pancake/opengl"
+)
+
+// UpscaleFilter selects the shader that presents the internal framebuffer
+// to the window when rendering at a lower resolution.
+type UpscaleFilter int
+
+const (
+	// UpscaleNone draws directly into the scaled window viewport (default).
+	UpscaleNone UpscaleFilter = iota
+
+	// UpscaleNearest renders into an internal framebuffer at the logical
+	// resolution and presents it using nearest neighbour sampling.
+	UpscaleNearest
+
+	// UpscaleSharpBilinear is like UpscaleNearest but interpolates the pixel edges,
+	// which keeps pixel art crisp when the scale factor is fractional.
+	UpscaleSharpBilinear
+
+	// UpscaleScanlines is like UpscaleSharpBilinear but darkens the edges of
+	// every row of pixels to mimic the scanlines of a CRT display.
+	UpscaleScanlines
+)
+
+// UpscaleVertexShader is the vertex shader of the upscale pass.
+// It draws a full screen quad with the following attributes and outputs:
+//
+//	layout(location = 0) in vec2 in_Position;
+//	layout(location = 1) in vec2 in_Texture;
+//	out vec2 f_Texture;
+//
+// Upscale fragment shaders receive the following uniforms:
+//
+//	uniform sampler2D u_Texture;  // the internal framebuffer
+//	uniform vec2 u_TextureSize;   // the logical resolution in pixels
+//	uniform vec2 u_OutputSize;    // the window viewport in pixels
+const UpscaleVertexShader = `
+#version 330 core
+
+layout(location = 0) in vec2 in_Position;
+layout(location = 1) in vec2 in_Texture;
+
+out vec2 f_Texture;
+
+void main()
+{
+	f_Texture = in_Texture;
+	gl_Position = vec4(in_Position, 0, 1);
+}
+`
+
+const upscaleNearestShader = `
+#version 330 core
+
+in vec2 f_Texture;
+
+out vec4 out_FragColor;
+
+uniform sampler2D u_Texture;
+uniform vec2 u_TextureSize;
+
+void main()
+{
+	out_FragColor = texelFetch(u_Texture, ivec2(f_Texture * u_TextureSize), 0);
+}
+`
+
+const upscaleSharpBilinearShader = `
+#version 330 core
+
+in vec2 f_Texture;
+
+out vec4 out_FragColor;
+
+uniform sampler2D u_Texture;
+uniform vec2 u_TextureSize;
+uniform vec2 u_OutputSize;
+
+void main()
+{
+	vec2 texel = f_Texture * u_TextureSize;
+	vec2 scale = max(floor(u_OutputSize / u_TextureSize), vec2(1));
+	vec2 region = 0.5 - 0.5 / scale;
+	vec2 dist = fract(texel) - 0.5;
+	vec2 f = (dist - clamp(dist, -region, region)) * scale + 0.5;
+	out_FragColor = texture(u_Texture, (floor(texel) + f) / u_TextureSize);
+}
+`
+
+const upscaleScanlinesShader = `
+#version 330 core
+
+in vec2 f_Texture;
+
+out vec4 out_FragColor;
+
+uniform sampler2D u_Texture;
+uniform vec2 u_TextureSize;
+uniform vec2 u_OutputSize;
+
+void main()
+{
+	vec2 texel = f_Texture * u_TextureSize;
+	vec2 scale = max(floor(u_OutputSize / u_TextureSize), vec2(1));
+	vec2 region = 0.5 - 0.5 / scale;
+	vec2 dist = fract(texel) - 0.5;
+	vec2 f = (dist - clamp(dist, -region, region)) * scale + 0.5;
+	vec4 color = texture(u_Texture, (floor(texel) + f) / u_TextureSize);
+	float scanline = 0.75 + 0.25 * cos(6.28318530718 * dist.y);
+	out_FragColor = vec4(color.rgb * scanline, color.a);
+}
+`
+
+var upscaleQuadFormat = AttribFormat{
+	AttribVec2, // x, y
+	AttribVec2, // u, v
+}
+
+var upscaleQuadVertices = []float64{
+	-1, -1, 0, 0,
+	+1, -1, 1, 0,
+	-1, +1, 0, 1,
+	+1, +1, 1, 1,
+}
+
+func (filter UpscaleFilter) fragmentShader() string {
+	switch filter {
+	case UpscaleSharpBilinear:
+		return upscaleSharpBilinearShader
+	case UpscaleScanlines:
+		return upscaleScanlinesShader
+	default:
+		return upscaleNearestShader
+	}
+}
+
+// NewUpscaleShader compiles a custom upscale fragment shader
+// together with UpscaleVertexShader.
+func NewUpscaleShader(fshader string) (*ShaderProgram, error) {
+	return NewShaderProgram(UpscaleVertexShader, fshader)
+}
+
+// upscaler renders into an internal framebuffer at the logical resolution
+// and presents it to the window viewport.
+type upscaler struct {
+	filter    UpscaleFilter
+	shader    *ShaderProgram
+	ownShader bool
+	canvas    *Framebuffer
+	samples   int
+	msaa      *Framebuffer
+	quad      *VertexArraySlice
+}
+
+func (u *upscaler) setShader(shader *ShaderProgram) {
+	if u.ownShader {
+		u.shader.Delete()
+	}
+	u.shader, u.ownShader = shader, false
+}
+
+// delete deletes the resources created by the upscaler.
+func (u *upscaler) delete() {
+	if u.canvas != nil {
+		u.canvas.Delete()
+	}
+	if u.msaa != nil {
+		u.msaa.Delete()
+	}
+	if u.ownShader {
+		u.shader.Delete()
+	}
+	if u.quad != nil {
+		u.quad.Delete()
+		u.quad.Buffer().Delete()
+	}
+}
+
+// begin binds the internal framebuffer, recreating it if the resolution has changed.
+func (u *upscaler) begin(resolution image.Point) {
+	if u.canvas == nil || u.canvas.Bounds().Size() != resolution {
+		canvas, err := NewFramebuffer(resolution, FilterLinear, true)
+		if err != nil {
+			panic(err)
+		}
+		if u.canvas != nil {
+			u.canvas.Delete()
+		}
+		u.canvas = canvas
+
+		if u.samples > 0 {
+			msaa, err := NewMultisampleFramebuffer(resolution, u.samples, true)
+			if err != nil {
+				panic(err)
+			}
+			if u.msaa != nil {
+				u.msaa.Delete()
+			}
+			u.msaa = msaa
+		}
+	}
+
+	if u.shader == nil {
+		shader, err := NewUpscaleShader(u.filter.fragmentShader())
+		if err != nil {
+			panic(err)
+		}
+		u.shader, u.ownShader = shader, true
+	}
+
+	if u.quad == nil {
+		vbo := NewVertexBuffer(upscaleQuadFormat, 4, upscaleQuadVertices)
+		u.quad = NewVertexArraySlice(vbo)
+	}
+
+	u.target().Begin()
+	gl.Viewport(u.canvas.Bounds())
+}
+
+// target returns the framebuffer that is drawn into.
+func (u *upscaler) target() *Framebuffer {
+	if u.msaa != nil {
+		return u.msaa
+	}
+	return u.canvas
+}
+
+// end unbinds the internal framebuffer and draws it to the window viewport.
+func (u *upscaler) end(viewport image.Rectangle) {
+	// the upscale pass is not counted as a draw call of the frame
+	counter := drawCounter
+	defer func() { drawCounter = counter }()
+
+	u.target().End()
+	if u.msaa != nil {
+		u.msaa.Resolve(u.canvas)
+	}
+
+	ZeroScissor.Begin()
+	defer ZeroScissor.End()
+	BlendNone.Begin()
+	defer BlendNone.End()
+	DepthNone.Begin()
+	defer DepthNone.End()
+	CullState{}.Begin()
+	defer CullState{}.End()
+
+	gl.Viewport(viewport)
+	gl.ClearColor(0, 0, 0, 1)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+
+	color := u.canvas.Color()
+	color.Begin()
+	defer color.End()
+
+	u.shader.Begin()
+	defer u.shader.End()
+	u.shader.SetUniform("u_Texture", 0)
+	u.shader.SetUniform("u_TextureSize", mathx.FromPoint(color.Size()))
+	u.shader.SetUniform("u_OutputSize", mathx.FromPoint(viewport.Size()))
+
+	u.quad.Begin()
+	defer u.quad.End()
+	u.quad.Draw(gl.TRIANGLE_STRIP)
+}
@@ -0,0 +1,22 @@
+package pancake
+
+import (
+	"image"
+	"testing"
+)
+
+func TestUpscalerDrawStats(t *testing.T) {
+	u := &upscaler{filter: UpscaleNearest}
+	defer u.delete()
+
+	var ft frameTimer
+	ft.beginFrame()
+	u.begin(image.Point{32, 20})
+	countDraw(6)
+	u.end(image.Rect(0, 0, 320, 200))
+	ft.endFrame()
+
+	if stats := ft.stats(); stats.DrawCalls != 1 || stats.Vertices != 6 {
+		t.Fatal(stats.DrawCalls, stats.Vertices)
+	}
+}
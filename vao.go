@@ -42,8 +42,22 @@ func (vao *vertexArrayObject) slice(i, j int) *VertexArraySlice {
 	panic(fmt.Errorf("range out of bounds"))
 }
 
+func (vao *vertexArrayObject) Delete() {
+	if vao.id != 0 {
+		runtime.SetFinalizer(vao, nil)
+		vao.delete()
+	}
+}
+
 func (vao *vertexArrayObject) delete() {
+	vaobinder.forget(uint32(vao.id))
+	untrackObject("vertex array", uint32(vao.id))
 	gl.DeleteVertexArray(vao.id)
+	vao.id = 0
+}
+
+func (vao *vertexArrayObject) finalize() {
+	queueCall(vao.delete)
 }
 
 type VertexArraySlice struct {
@@ -51,6 +65,12 @@ type VertexArraySlice struct {
 	i, j int
 }
 
+// Delete deletes the vertex array shared by all slices of it.
+// The VertexBuffer is not deleted.
+func (vas *VertexArraySlice) Delete() {
+	vas.vao.Delete()
+}
+
 func (vas *VertexArraySlice) Begin() {
 	vas.vao.begin()
 }
@@ -109,7 +129,8 @@ func NewVertexArraySlice(vbo *VertexBuffer) *VertexArraySlice {
 		id:  gl.CreateVertexArray(),
 	}
 
-	runtime.SetFinalizer(vao, (*vertexArrayObject).delete)
+	trackObject("vertex array", uint32(vao.id))
+	runtime.SetFinalizer(vao, (*vertexArrayObject).finalize)
 
 	vao.begin()
 	defer vao.end()
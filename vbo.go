@@ -45,13 +45,29 @@ func (vbo *VertexBuffer) SetData(i, j int, data interface{}) {
 func (vbo *VertexBuffer) Draw(mode gl.Enum, i, j int) {
 	if j > i && i >= 0 && j <= vbo.count {
 		gl.DrawArrays(mode, i, j-i)
+		countDraw(j - i)
 	} else {
 		panic(errors.New("range out of bounds"))
 	}
 }
 
+// Delete deletes the VertexBuffer. It is safe to call Delete more than once.
+func (vbo *VertexBuffer) Delete() {
+	if vbo.id != 0 {
+		runtime.SetFinalizer(vbo, nil)
+		vbo.delete()
+	}
+}
+
 func (vbo *VertexBuffer) delete() {
+	vbobinder.forget(uint32(vbo.id))
+	untrackObject("buffer", uint32(vbo.id))
 	gl.DeleteBuffer(vbo.id)
+	vbo.id = 0
+}
+
+func (vbo *VertexBuffer) finalize() {
+	queueCall(vbo.delete)
 }
 
 func NewVertexBuffer(format AttribFormat, count int, data interface{}) *VertexBuffer {
@@ -62,7 +78,8 @@ func NewVertexBuffer(format AttribFormat, count int, data interface{}) *VertexBu
 		id:     gl.CreateBuffer(),
 	}
 
-	runtime.SetFinalizer(buf, (*VertexBuffer).delete)
+	trackObject("buffer", uint32(buf.id))
+	runtime.SetFinalizer(buf, (*VertexBuffer).finalize)
 
 	buf.Begin()
 	defer buf.End()
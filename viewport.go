@@ -2,9 +2,56 @@ package pancake
 
 import (
 	"image"
+	"math"
 )
 
-func logicalViewport(window, resolution image.Point) image.Rectangle {
+// ScalingMode determines how the logical resolution is scaled to the window.
+type ScalingMode int
+
+const (
+	// ScalingInteger scales by the largest integer factor that fits the window
+	// and centers the viewport with letterbox borders. This is pixel-perfect.
+	ScalingInteger ScalingMode = iota
+
+	// ScalingAspect scales by the largest fractional factor that fits the window
+	// while preserving the aspect ratio of the resolution.
+	ScalingAspect
+
+	// ScalingStretch stretches the resolution to fill the entire window
+	// without preserving the aspect ratio.
+	ScalingStretch
+
+	// ScalingExpand scales by an integer factor like ScalingInteger but expands
+	// the logical resolution so that it fills the window.
+	ScalingExpand
+)
+
+// logicalViewport computes the viewport in window pixels and the logical resolution
+// that results from scaling resolution to the window according to the scaling mode.
+func logicalViewport(window, resolution image.Point, mode ScalingMode) (image.Rectangle, image.Point) {
+	switch mode {
+	case ScalingAspect:
+		sx := float64(window.X) / float64(resolution.X)
+		sy := float64(window.Y) / float64(resolution.Y)
+		scale := math.Min(sx, sy)
+		logical := image.Point{
+			int(math.Round(float64(resolution.X) * scale)),
+			int(math.Round(float64(resolution.Y) * scale)),
+		}
+		return centerViewport(window, logical), resolution
+	case ScalingStretch:
+		return image.Rectangle{Max: window}, resolution
+	case ScalingExpand:
+		scale := integerScale(window, resolution)
+		expanded := window.Div(scale)
+		return centerViewport(window, expanded.Mul(scale)), expanded
+	default:
+		scale := integerScale(window, resolution)
+		return centerViewport(window, resolution.Mul(scale)), resolution
+	}
+}
+
+func integerScale(window, resolution image.Point) int {
 	wAspectRatio := float32(window.X) / float32(window.Y)
 	rAspectRatio := float32(resolution.X) / float32(resolution.Y)
 	var scale int
@@ -15,7 +62,15 @@ func logicalViewport(window, resolution image.Point) image.Rectangle {
 		scale = window.X / resolution.X
 	}
 
-	logical := resolution.Mul(scale)
+	// never scale below the logical resolution
+	if scale < 1 {
+		scale = 1
+	}
+
+	return scale
+}
+
+func centerViewport(window, logical image.Point) image.Rectangle {
 	crop := window.Sub(logical)
 	border := crop.Div(2)
 
@@ -24,3 +79,22 @@ func logicalViewport(window, resolution image.Point) image.Rectangle {
 		border.Add(logical),
 	}
 }
+
+// viewportToLogical maps a point in window pixels to logical coordinates.
+func viewportToLogical(p image.Point, viewport image.Rectangle, resolution image.Point) image.Point {
+	p = p.Sub(viewport.Min)
+	vpsz := viewport.Size()
+	return image.Point{
+		p.X * resolution.X / vpsz.X,
+		p.Y * resolution.Y / vpsz.Y,
+	}
+}
+
+// logicalToViewport maps a point in logical coordinates to window pixels.
+func logicalToViewport(p image.Point, viewport image.Rectangle, resolution image.Point) image.Point {
+	vpsz := viewport.Size()
+	return image.Point{
+		p.X * vpsz.X / resolution.X,
+		p.Y * vpsz.Y / resolution.Y,
+	}.Add(viewport.Min)
+}
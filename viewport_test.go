@@ -18,9 +18,96 @@ func TestLogicalViewport(t *testing.T) {
 	}
 
 	for _, x := range tests {
-		logical := logicalViewport(screen, x.resolution)
-		if logical != x.viewport {
+		logical, resolution := logicalViewport(screen, x.resolution, ScalingInteger)
+		if logical != x.viewport || resolution != x.resolution {
 			t.Fatal(x.resolution)
 		}
 	}
 }
+
+func TestLogicalViewportSmallWindow(t *testing.T) {
+	logical, _ := logicalViewport(image.Point{320, 200}, image.Point{640, 360}, ScalingInteger)
+	if logical != image.Rect(-160, -80, 480, 280) {
+		t.Fatal(logical)
+	}
+}
+
+func TestLogicalViewportAspect(t *testing.T) {
+	screen := image.Point{1920, 1080}
+
+	tests := []struct {
+		resolution image.Point
+		viewport   image.Rectangle
+	}{
+		{image.Point{640, 360}, image.Rect(0, 0, 1920, 1080)},
+		{image.Point{640, 400}, image.Rect(96, 0, 1824, 1080)},
+		{image.Point{400, 300}, image.Rect(240, 0, 1680, 1080)},
+		{image.Point{800, 300}, image.Rect(0, 180, 1920, 900)},
+	}
+
+	for _, x := range tests {
+		logical, resolution := logicalViewport(screen, x.resolution, ScalingAspect)
+		if logical != x.viewport || resolution != x.resolution {
+			t.Fatal(x.resolution, logical)
+		}
+	}
+}
+
+func TestLogicalViewportStretch(t *testing.T) {
+	screen := image.Point{1920, 1080}
+
+	tests := []image.Point{
+		{640, 360},
+		{640, 400},
+		{400, 300},
+	}
+
+	for _, x := range tests {
+		logical, resolution := logicalViewport(screen, x, ScalingStretch)
+		if logical != image.Rect(0, 0, 1920, 1080) || resolution != x {
+			t.Fatal(x, logical)
+		}
+	}
+}
+
+func TestLogicalViewportExpand(t *testing.T) {
+	screen := image.Point{1920, 1080}
+
+	tests := []struct {
+		resolution image.Point
+		viewport   image.Rectangle
+		expanded   image.Point
+	}{
+		{image.Point{640, 360}, image.Rect(0, 0, 1920, 1080), image.Point{640, 360}},
+		{image.Point{640, 400}, image.Rect(0, 0, 1920, 1080), image.Point{960, 540}},
+		{image.Point{400, 300}, image.Rect(0, 0, 1920, 1080), image.Point{640, 360}},
+		{image.Point{500, 500}, image.Rect(0, 0, 1920, 1080), image.Point{960, 540}},
+		{image.Point{700, 500}, image.Rect(0, 0, 1920, 1080), image.Point{960, 540}},
+	}
+
+	for _, x := range tests {
+		logical, resolution := logicalViewport(screen, x.resolution, ScalingExpand)
+		if logical != x.viewport || resolution != x.expanded {
+			t.Fatal(x.resolution, logical, resolution)
+		}
+	}
+}
+
+func TestLogicalViewportExpandRemainder(t *testing.T) {
+	logical, resolution := logicalViewport(image.Point{1001, 601}, image.Point{320, 200}, ScalingExpand)
+	if logical != image.Rect(1, 0, 1000, 600) || resolution != (image.Point{333, 200}) {
+		t.Fatal(logical, resolution)
+	}
+}
+
+func TestViewportMapping(t *testing.T) {
+	viewport := image.Rect(320, 140, 1600, 940)
+	resolution := image.Point{640, 400}
+
+	p := logicalToViewport(image.Point{100, 50}, viewport, resolution)
+	if p != (image.Point{520, 240}) {
+		t.Fatal(p)
+	} else if q := viewportToLogical(p, viewport, resolution); q != (image.Point{100, 50}) {
+		t.Fatal(q)
+	}
+}